@@ -0,0 +1,244 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// OverflowPolicy selects what Put does with a value when a BoundedBuffer is
+// full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Put wait for room, or for its context to be done.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the value passed to Put.
+	OverflowDropNewest
+	// OverflowDropOldest drops the earliest buffered value to make room.
+	OverflowDropOldest
+	// OverflowError makes Put fail with ErrBufferFull.
+	OverflowError
+)
+
+// ErrBufferFull is returned by Put of a full BoundedBuffer with the
+// OverflowError policy.
+var ErrBufferFull = errors.New("gosync: buffer full")
+
+var errBoundedBufferClosed = errors.New("gosync: Put called on closed BoundedBuffer")
+
+// BufferStats is a snapshot of the state of a BoundedBuffer.
+type BufferStats struct {
+	// Len is the number of buffered values.
+	Len int
+	// Dropped is the number of values dropped by the OverflowDropNewest and
+	// OverflowDropOldest policies.
+	Dropped uint64
+	// Rejected is the number of calls to Put that failed with ErrBufferFull.
+	Rejected uint64
+}
+
+// BoundedBuffer is like Unbounded, but holds at most a fixed number of values.
+// What happens to a value Put into a full buffer is decided by its
+// OverflowPolicy.
+//
+// Like with Unbounded, values are read from the channel returned by Get,
+// followed by a call to Load, or with Recv, TryRecv and All.
+//
+// All methods on this type are thread-safe.
+type BoundedBuffer[T any] struct {
+	c        chan T
+	capacity int
+	policy   OverflowPolicy
+	closed   bool
+	closing  bool
+	mu       sync.Mutex
+	backlog  ring[T]
+	room     chan struct{} // closed when a value is read, if not nil
+	dropped  uint64
+	rejected uint64
+}
+
+// NewBoundedBuffer returns a BoundedBuffer holding up to capacity values.
+func NewBoundedBuffer[T any](capacity int, policy OverflowPolicy) *BoundedBuffer[T] {
+	if capacity < 1 {
+		panic("gosync: BoundedBuffer capacity must be at least 1")
+	}
+	return &BoundedBuffer[T]{c: make(chan T, 1), capacity: capacity, policy: policy}
+}
+
+// len returns the number of buffered values. b.mu must be held.
+func (b *BoundedBuffer[T]) len() int {
+	return len(b.c) + b.backlog.len()
+}
+
+// Put adds t to the buffer. If the buffer is full, Put applies the overflow
+// policy of the buffer; with OverflowBlock it returns ctx.Err() if ctx is done
+// before there is room for t. Dropping a value is not an error.
+func (b *BoundedBuffer[T]) Put(ctx context.Context, t T) error {
+	b.mu.Lock()
+	for {
+		if b.closing {
+			b.mu.Unlock()
+			return errBoundedBufferClosed
+		}
+		if b.len() < b.capacity {
+			break
+		}
+		switch b.policy {
+		case OverflowDropNewest:
+			b.dropped++
+			b.mu.Unlock()
+			return nil
+		case OverflowDropOldest:
+			b.dropped++
+			b.dropOldest()
+		case OverflowError:
+			b.rejected++
+			b.mu.Unlock()
+			return ErrBufferFull
+		default:
+			if b.room == nil {
+				b.room = make(chan struct{})
+			}
+			room := b.room
+			b.mu.Unlock()
+			select {
+			case <-room:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			b.mu.Lock()
+		}
+	}
+	defer b.mu.Unlock()
+	if b.backlog.len() == 0 {
+		select {
+		case b.c <- t:
+			return nil
+		default:
+		}
+	}
+	b.backlog.push(t)
+	return nil
+}
+
+// dropOldest drops the earliest buffered value. b.mu must be held.
+func (b *BoundedBuffer[T]) dropOldest() {
+	select {
+	case <-b.c:
+		b.load()
+		return
+	default:
+	}
+	// A reader took the value on the channel but did not call Load yet.
+	b.backlog.pop()
+}
+
+// Load sends the earliest buffered value, if any, onto the read channel
+// returned by Get. Users are expected to call this every time they
+// successfully read a value from the read channel.
+func (b *BoundedBuffer[T]) Load() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	if b.room != nil {
+		close(b.room)
+		b.room = nil
+	}
+}
+
+func (b *BoundedBuffer[T]) load() {
+	if b.backlog.len() > 0 {
+		select {
+		case b.c <- b.backlog.peek():
+			b.backlog.pop()
+		default:
+		}
+	} else if b.closing && !b.closed {
+		b.closed = true
+		close(b.c)
+	}
+}
+
+// Get returns a read channel on which values added to the buffer are sent.
+// Upon reading a value from this channel, users are expected to call Load.
+//
+// If the buffer is closed, the read channel is closed after all data is
+// drained.
+func (b *BoundedBuffer[T]) Get() <-chan T {
+	return b.c
+}
+
+// Recv blocks until a value is available and returns it, calling Load on
+// behalf of the caller. It returns ErrBufferDrained once the buffer is closed
+// and drained, or ctx.Err() if ctx is done first.
+func (b *BoundedBuffer[T]) Recv(ctx context.Context) (T, error) {
+	select {
+	case t, ok := <-b.c:
+		if !ok {
+			return t, ErrBufferDrained
+		}
+		b.Load()
+		return t, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// TryRecv is like Recv, but does not block: it reports false if no value is
+// available right now or the buffer is closed and drained.
+func (b *BoundedBuffer[T]) TryRecv() (T, bool) {
+	select {
+	case t, ok := <-b.c:
+		if ok {
+			b.Load()
+		}
+		return t, ok
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+// All returns an iterator that yields the values of the buffer as they are
+// received with Recv, like Unbounded.All.
+func (b *BoundedBuffer[T]) All(ctx context.Context) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for {
+			t, err := b.Recv(ctx)
+			if err != nil || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Stats returns a snapshot of the state of b. Alert on Dropped and Rejected to
+// notice a consumer that cannot keep up.
+func (b *BoundedBuffer[T]) Stats() BufferStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BufferStats{Len: b.len(), Dropped: b.dropped, Rejected: b.rejected}
+}
+
+// Close closes the buffer. No subsequent data may be Put, and blocked calls to
+// Put fail. The channel returned from Get is closed after all the data is read
+// and Load is called for the final time.
+func (b *BoundedBuffer[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closing {
+		return
+	}
+	b.closing = true
+	if b.room != nil {
+		close(b.room)
+		b.room = nil
+	}
+	if b.backlog.len() == 0 {
+		b.closed = true
+		close(b.c)
+	}
+}
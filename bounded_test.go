@@ -0,0 +1,155 @@
+package gosync
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// drain receives all values of b, which must be closed.
+func drain(b *BoundedBuffer[int]) []int {
+	var reads []int
+	b.All(context.Background())(func(v int) bool {
+		reads = append(reads, v)
+		return true
+	})
+	return reads
+}
+
+func TestBoundedBufferDrop(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		policy OverflowPolicy
+		want   []int
+	}{
+		{OverflowDropNewest, []int{0, 1, 2}},
+		{OverflowDropOldest, []int{2, 3, 4}},
+	} {
+		b := NewBoundedBuffer[int](3, tt.policy)
+		for i := 0; i < 5; i++ {
+			if err := b.Put(ctx, i); err != nil {
+				t.Fatalf("Put(%d) with policy %d = %v; want nil", i, tt.policy, err)
+			}
+		}
+		if s := b.Stats(); s.Len != 3 || s.Dropped != 2 || s.Rejected != 0 {
+			t.Fatalf("Stats() with policy %d = %+v; want 3 values and 2 dropped", tt.policy, s)
+		}
+		b.Close()
+		if reads := drain(b); !reflect.DeepEqual(reads, tt.want) {
+			t.Fatalf("read %v with policy %d; want %v", reads, tt.policy, tt.want)
+		}
+	}
+}
+
+// TestBoundedBufferDropOldestUnloaded drops the oldest value while a reader
+// holds the value it took from the channel without calling Load yet.
+func TestBoundedBufferDropOldestUnloaded(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](2, OverflowDropOldest)
+	b.Put(ctx, 0)
+	b.Put(ctx, 1)
+	if v := <-b.Get(); v != 0 {
+		t.Fatalf("Get() = %d; want 0", v)
+	}
+	b.Put(ctx, 2)
+	b.Put(ctx, 3) // drops 1
+	b.Load()
+	b.Close()
+	if reads, want := drain(b), []int{2, 3}; !reflect.DeepEqual(reads, want) {
+		t.Fatalf("read %v; want %v", reads, want)
+	}
+}
+
+func TestBoundedBufferError(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](1, OverflowError)
+	if err := b.Put(ctx, 0); err != nil {
+		t.Fatalf("Put() = %v; want nil", err)
+	}
+	if err := b.Put(ctx, 1); err != ErrBufferFull {
+		t.Fatalf("Put() on full buffer = %v; want %v", err, ErrBufferFull)
+	}
+	if s := b.Stats(); s.Rejected != 1 || s.Dropped != 0 {
+		t.Fatalf("Stats() = %+v; want 1 rejected", s)
+	}
+	b.TryRecv()
+	if err := b.Put(ctx, 1); err != nil {
+		t.Fatalf("Put() after TryRecv() = %v; want nil", err)
+	}
+}
+
+func TestBoundedBufferBlock(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](1, OverflowBlock)
+	b.Put(ctx, 0)
+
+	short, cancel := context.WithTimeout(ctx, defaultTestShortTimeout)
+	defer cancel()
+	if err := b.Put(short, 1); err != context.DeadlineExceeded {
+		t.Fatalf("Put() on full buffer = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	put := make(chan error)
+	go func() { put <- b.Put(ctx, 1) }()
+	select {
+	case err := <-put:
+		t.Fatalf("Put() on full buffer returned %v; want it to block", err)
+	case <-time.After(defaultTestShortTimeout):
+	}
+	if v, err := b.Recv(ctx); v != 0 || err != nil {
+		t.Fatalf("Recv() = %v, %v; want 0, <nil>", v, err)
+	}
+	select {
+	case err := <-put:
+		if err != nil {
+			t.Fatalf("blocked Put() = %v; want nil", err)
+		}
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Recv() to unblock Put()")
+	}
+
+	go func() { put <- b.Put(ctx, 2) }()
+	time.Sleep(defaultTestShortTimeout)
+	b.Close()
+	select {
+	case err := <-put:
+		if err != errBoundedBufferClosed {
+			t.Fatalf("Put() blocked on a closed buffer = %v; want %v", err, errBoundedBufferClosed)
+		}
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Close() to unblock Put()")
+	}
+	if reads, want := drain(b), []int{1}; !reflect.DeepEqual(reads, want) {
+		t.Fatalf("read %v; want %v", reads, want)
+	}
+}
+
+func TestBoundedBufferRace(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](4, OverflowBlock)
+	done := make(chan []int)
+	go func() {
+		var reads []int
+		for v := range b.Get() {
+			reads = append(reads, v)
+			b.Load()
+		}
+		done <- reads
+	}()
+	for i := 0; i < 1_000; i++ {
+		if err := b.Put(ctx, i); err != nil {
+			t.Fatalf("Put() = %v; want nil", err)
+		}
+	}
+	b.Close()
+	reads := <-done
+	for i, v := range reads {
+		if v != i {
+			t.Fatalf("read %d at position %d; want values in order", v, i)
+		}
+	}
+	if len(reads) != 1_000 {
+		t.Fatalf("read %d values; want 1000", len(reads))
+	}
+}
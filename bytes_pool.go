@@ -0,0 +1,75 @@
+package gosync
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// BytesPool is a pool of byte slices sorted into power-of-two size classes,
+// so that a request for n bytes is served by a slice of at most twice that
+// capacity.
+//
+// All methods on this type are thread-safe.
+type BytesPool struct {
+	minShift int
+	maxShift int
+	// classes[i] holds the backing arrays of slices with a capacity of at
+	// least 1<<(minShift+i), stored as unsafe.Pointer so that Put does not
+	// allocate.
+	classes []sync.Pool
+}
+
+// NewBytesPool returns a BytesPool with size classes from minSize up to
+// maxSize bytes, both rounded up to a power of two. Requests larger than
+// maxSize are allocated and never pooled.
+func NewBytesPool(minSize, maxSize int) *BytesPool {
+	if minSize <= 0 || maxSize < minSize {
+		panic("gosync: BytesPool needs 0 < minSize <= maxSize")
+	}
+	p := &BytesPool{
+		minShift: ceilLog2(minSize),
+		maxShift: ceilLog2(maxSize),
+	}
+	p.classes = make([]sync.Pool, p.maxShift-p.minShift+1)
+	return p
+}
+
+// ceilLog2 returns the smallest k >= 0 with 1<<k >= n.
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// Get returns a slice of length n whose capacity is the size class of n. The
+// content of the slice is undefined.
+func (p *BytesPool) Get(n int) []byte {
+	shift := ceilLog2(n)
+	if shift < p.minShift {
+		shift = p.minShift
+	}
+	if shift > p.maxShift {
+		return make([]byte, n)
+	}
+	if ptr, ok := p.classes[shift-p.minShift].Get().(unsafe.Pointer); ok {
+		return unsafe.Slice((*byte)(ptr), 1<<shift)[:n]
+	}
+	return make([]byte, n, 1<<shift)
+}
+
+// Put returns b to the pool, in the largest size class its capacity covers.
+// Slices smaller than the smallest class or larger than the largest class are
+// dropped. b must not be used after Put.
+func (p *BytesPool) Put(b []byte) {
+	c := cap(b)
+	if c == 0 {
+		return
+	}
+	shift := bits.Len(uint(c)) - 1 // floor(log2(c))
+	if shift < p.minShift || c > 1<<p.maxShift {
+		return
+	}
+	p.classes[shift-p.minShift].Put(unsafe.Pointer(unsafe.SliceData(b[:1])))
+}
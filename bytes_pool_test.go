@@ -0,0 +1,136 @@
+package gosync
+
+import (
+	"runtime/debug"
+	"sync"
+	"testing"
+)
+
+func TestBytesPoolGet(t *testing.T) {
+	p := NewBytesPool(64, 1000)
+	for _, tc := range []struct {
+		n, cap int
+	}{
+		{0, 64},
+		{1, 64},
+		{64, 64},
+		{65, 128},
+		{1000, 1024},
+		{1024, 1024},
+		{1025, 1025}, // too large to pool
+	} {
+		b := p.Get(tc.n)
+		if len(b) != tc.n || cap(b) != tc.cap {
+			t.Errorf("Get(%d) = len %d, cap %d; want len %d, cap %d", tc.n, len(b), cap(b), tc.n, tc.cap)
+		}
+	}
+}
+
+func TestBytesPoolReuse(t *testing.T) {
+	// Disable GC to avoid the victim cache during the test.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	p := NewBytesPool(64, 4096)
+	// See TestPoolNew for why objects are put many times.
+	for i := 0; i < 1_000; i++ {
+		b := make([]byte, 300) // cap 300 routes to the 256 class
+		b[0] = 42
+		p.Put(b)
+	}
+	b := p.Get(200)
+	if len(b) != 200 || cap(b) != 256 {
+		t.Fatalf("Get(200) = len %d, cap %d; want len 200, cap 256", len(b), cap(b))
+	}
+	if b[0] != 42 {
+		t.Fatal("Get(200) did not reuse a pooled slice")
+	}
+	// A request for the next class must not get a 300 bytes slice.
+	if b := p.Get(257); cap(b) != 512 || b[0] == 42 {
+		t.Fatalf("Get(257) = cap %d; want a new slice of cap 512", cap(b))
+	}
+}
+
+func TestBytesPoolPutDiscards(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	p := NewBytesPool(64, 1024)
+	for i := 0; i < 1_000; i++ {
+		small := make([]byte, 32)
+		small[0] = 1
+		p.Put(small)
+		huge := make([]byte, 1<<20)
+		huge[0] = 1
+		p.Put(huge)
+	}
+	p.Put(nil)
+	if b := p.Get(1); b[0] == 1 {
+		t.Fatal("Put() pooled a slice smaller than the smallest class")
+	}
+	if b := p.Get(1024); b[0] == 1 {
+		t.Fatal("Put() pooled an oversized slice")
+	}
+}
+
+func TestBytesPoolRace(t *testing.T) {
+	p := NewBytesPool(16, 1<<16)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b := p.Get((i*j)%(1<<17) + 1)
+				for k := range b {
+					b[k] = byte(i)
+				}
+				p.Put(b)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+var bytesBenchSizes = []int{100, 1500, 4000, 16000, 60000}
+
+func BenchmarkBytesPool(b *testing.B) {
+	b.Run("BytesPool", func(b *testing.B) {
+		p := NewBytesPool(64, 64<<10)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				buf := p.Get(bytesBenchSizes[i%len(bytesBenchSizes)])
+				buf[0] = 1
+				p.Put(buf)
+			}
+		})
+	})
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				buf := make([]byte, bytesBenchSizes[i%len(bytesBenchSizes)])
+				buf[0] = 1
+			}
+		})
+	})
+	b.Run("Pool", func(b *testing.B) {
+		// A single pool for all sizes, growing slices that are too small.
+		var p sync.Pool
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				n := bytesBenchSizes[i%len(bytesBenchSizes)]
+				bp, _ := p.Get().(*[]byte)
+				if bp == nil || cap(*bp) < n {
+					buf := make([]byte, n)
+					bp = &buf
+				}
+				buf := (*bp)[:n]
+				buf[0] = 1
+				*bp = buf
+				p.Put(bp)
+			}
+		})
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+// Adapted from golang.org/x/sync/errgroup.
+
+// Package errgroup provides the API of golang.org/x/sync/errgroup on top of
+// gosync.Group, so that code can move to gosync by changing its imports.
+//
+// Unlike x/sync/errgroup, a panic in a goroutine does not crash the program
+// right away: it is recovered and Wait re-panics with a *gosync.PanicError
+// once all goroutines have returned.
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/millken/gosync"
+)
+
+type token struct{}
+
+// A Group is a collection of goroutines working on subtasks that are part of
+// the same overall task.
+//
+// A zero Group is valid, has no limit on the number of active goroutines,
+// and does not cancel on error.
+type Group struct {
+	g      gosync.Group
+	once   sync.Once
+	cancel func(error)
+	sem    chan token
+
+	errOnce sync.Once
+	err     error
+}
+
+// init makes the underlying group re-panic, like x/sync/errgroup does.
+func (g *Group) init() {
+	g.once.Do(g.g.RepanicOnWait)
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx.
+//
+// The derived Context is canceled the first time a function passed to Go
+// returns a non-nil error or the first time Wait returns, whichever occurs
+// first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+}
+
+// Wait blocks until all function calls from the Go method have returned, then
+// returns the first non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	defer func() {
+		if g.cancel != nil {
+			g.cancel(nil)
+		}
+	}()
+	g.init()
+	g.g.Wait() // re-panics
+	return g.err
+}
+
+// Go calls the given function in a new goroutine. It blocks until the new
+// goroutine can be added without the number of active goroutines in the group
+// exceeding the configured limit.
+//
+// The first call to return a non-nil error cancels the group's context, if the
+// group was created by calling WithContext. The error will be returned by
+// Wait.
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- token{}
+	}
+	g.start(f)
+}
+
+// TryGo calls the given function in a new goroutine only if the number of
+// active goroutines in the group is currently below the configured limit.
+//
+// The return value reports whether the goroutine was started.
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- token{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+// start runs f in the underlying group. Like x/sync/errgroup, the first error
+// is recorded and the context canceled before the limit slot of f is
+// released, so a goroutine started in that slot sees the canceled context.
+func (g *Group) start(f func() error) {
+	g.init()
+	g.g.Go(func(context.Context) error {
+		defer g.done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.error(&gosync.PanicError{Value: r, Stack: debug.Stack()})
+				// Let the underlying group recover it for Wait.
+				panic(r)
+			}
+		}()
+		err := f()
+		if err != nil {
+			g.error(err)
+		}
+		return err
+	})
+}
+
+// error records err if it is the first error of the group and cancels the
+// context of the group.
+func (g *Group) error(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		if g.cancel != nil {
+			g.cancel(err)
+		}
+	})
+}
+
+// SetLimit limits the number of active goroutines in this group to at most n.
+// A negative value indicates no limit. A limit of zero will prevent any new
+// goroutines from being added.
+//
+// Any subsequent call to the Go method will block until it can add an active
+// goroutine without exceeding the configured limit.
+//
+// The limit must not be modified while any goroutines in the group are
+// active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("errgroup: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan token, n)
+}
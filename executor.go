@@ -0,0 +1,76 @@
+package gosync
+
+import "sync"
+
+// executor runs the tasks of a GOMAXPROCS limited Group on a fixed number of
+// workers.
+type executor interface {
+	// submit queues t for execution. It must not block.
+	submit(t *task)
+	// flush hands all queued tasks over to the workers. It is called by
+	// Wait and may block until the workers accepted them.
+	flush()
+	// stop lets the workers exit once every submitted task has run.
+	stop()
+}
+
+// chanExecutor feeds its workers through a channel. Tasks that do not fit in
+// the channel buffer are kept aside, for workers that finished a task or until
+// flush.
+type chanExecutor struct {
+	ch chan *task
+
+	mu      sync.Mutex
+	pending []*task
+}
+
+func newChanExecutor(workers int, run func(t *task)) *chanExecutor {
+	e := &chanExecutor{ch: make(chan *task, workers)}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for t := range e.ch {
+				for ; t != nil; t = e.next() {
+					run(t)
+				}
+			}
+		}()
+	}
+	return e
+}
+
+func (e *chanExecutor) submit(t *task) {
+	select {
+	case e.ch <- t:
+	default:
+		e.mu.Lock()
+		e.pending = append(e.pending, t)
+		e.mu.Unlock()
+	}
+}
+
+// next takes the earliest task kept aside, or returns nil.
+func (e *chanExecutor) next() *task {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.pending) == 0 {
+		return nil
+	}
+	t := e.pending[0]
+	e.pending[0] = nil
+	e.pending = e.pending[1:]
+	return t
+}
+
+func (e *chanExecutor) flush() {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+	for _, t := range pending {
+		e.ch <- t
+	}
+}
+
+func (e *chanExecutor) stop() {
+	close(e.ch) // let all receiver exit
+}
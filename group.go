@@ -4,22 +4,108 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"runtime/pprof"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// TaskInfo identifies a task of a Group.
+type TaskInfo struct {
+	// Index is the position of the task in its batch, in the order the
+	// tasks were passed to the group, starting at 0.
+	Index int
+	// Name is the name given to GoNamed, if any.
+	Name string
+	// Group is the name of the group, as set by SetName.
+	Group string
+}
+
+func (t TaskInfo) String() string {
+	s := "task " + strconv.Itoa(t.Index)
+	if t.Name != "" {
+		s = "task " + strconv.Quote(t.Name) + " (" + strconv.Itoa(t.Index) + ")"
+	}
+	if t.Group != "" {
+		s = t.Group + ": " + s
+	}
+	return s
+}
+
+// PanicError is the error recorded by a Group when one of its tasks panics.
+// Use errors.As to tell a panic apart from an ordinary task error.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+	// Task is the task that panicked.
+	Task TaskInfo
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("errgroup: panic recovered in %s: %v\n%s", p.Task, p.Value, p.Stack)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+type task struct {
+	f    func(ctx context.Context) error
+	info TaskInfo
+
+	// Used by the priority executor.
+	priority int
+	seq      uint64
+	score    int64
+}
+
 // A Group is a collection of goroutines working on subtasks that are part of
 // the same overall task.
 //
+// A Group may be reused for successive batches of tasks: after Wait returns,
+// call Reset and start the next batch with Go.
+//
 // A zero Group is valid and does not cancel on error.
 type Group struct {
 	err     error
 	wg      sync.WaitGroup
 	errOnce sync.Once
 
-	workerOnce sync.Once
-	ch         chan func(ctx context.Context) error
-	chs        []func(ctx context.Context) error
+	mu       sync.Mutex
+	maxprocs int
+	exec     executor
+	subs     []*Group
+	done     chan struct{}
+	active   int
+	started  bool
+	drain    chan struct{}
+	draining bool
+	n        int64
+
+	priority bool
+	aging    time.Duration
+	stealing bool
+	limiter  Limiter
 
+	panicHandler func(p *PanicError) error
+	repanic      bool
+	panicked     atomic.Pointer[PanicError]
+
+	counters groupCounters
+	hooks    GroupHooks
+
+	name   string
+	labels []string
+
+	up     *Group
+	policy SubPolicy
+
+	parent context.Context
 	ctx    context.Context
 	cancel func()
 }
@@ -37,50 +123,176 @@ func WithContext(ctx context.Context) *Group {
 // returns a non-nil error or the first time Wait returns, whichever occurs
 // first.
 func WithCancel(ctx context.Context) *Group {
-	ctx, cancel := context.WithCancel(ctx)
-	return &Group{ctx: ctx, cancel: cancel}
+	g := &Group{parent: ctx}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	return g
 }
 
-func (g *Group) do(f func(ctx context.Context) error) {
+func (g *Group) do(t *task) {
 	ctx := g.ctx
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	if g.limiter != nil {
+		if err := g.limiter.Wait(ctx); err != nil {
+			g.counters.queued.Add(-1)
+			g.counters.failed.Add(1)
+			if g.hooks.OnFinish != nil {
+				g.hooks.OnFinish(t.info, 0, err)
+			}
+			g.fail(err)
+			g.end()
+			g.wg.Done()
+			return
+		}
+	}
+	g.counters.queued.Add(-1)
+	g.counters.running.Add(1)
+	if g.hooks.OnStart != nil {
+		g.hooks.OnStart(t.info)
+	}
+	var start time.Time
+	if g.hooks.OnFinish != nil {
+		start = time.Now()
+	}
 	var err error
 	defer func() {
+		g.counters.running.Add(-1)
 		if r := recover(); r != nil {
 			buf := make([]byte, 64<<10)
 			buf = buf[:runtime.Stack(buf, false)]
-			err = fmt.Errorf("errgroup: panic recovered: %s\n%s", r, buf)
+			g.counters.panicked.Add(1)
+			err = g.recovered(&PanicError{Value: r, Stack: buf, Task: t.info})
+		} else if err != nil {
+			g.counters.failed.Add(1)
+		} else {
+			g.counters.succeeded.Add(1)
+		}
+		if g.hooks.OnFinish != nil {
+			g.hooks.OnFinish(t.info, time.Since(start), err)
 		}
 		if err != nil {
-			g.errOnce.Do(func() {
-				g.err = err
-				if g.cancel != nil {
-					g.cancel()
-				}
-			})
+			g.fail(err)
 		}
+		g.end()
 		g.wg.Done()
 	}()
-	err = f(ctx)
+	if g.name == "" && len(g.labels) == 0 && t.info.Name == "" {
+		err = t.f(ctx)
+		return
+	}
+	pprof.Do(ctx, g.pprofLabels(t), func(ctx context.Context) {
+		err = t.f(ctx)
+	})
+	if err != nil && t.info.Name != "" {
+		err = fmt.Errorf("errgroup: %s: %w", t.info, err)
+	}
+}
+
+func (g *Group) pprofLabels(t *task) pprof.LabelSet {
+	labels := append([]string{"task_index", strconv.Itoa(t.info.Index)}, g.labels...)
+	if g.name != "" {
+		labels = append(labels, "group", g.name)
+	}
+	if t.info.Name != "" {
+		labels = append(labels, "task", t.info.Name)
+	}
+	return pprof.Labels(labels...)
+}
+
+// SetName names g. Tasks of a named group run under the runtime/pprof label
+// "group", and the name shows up in TaskInfo and PanicError.
+func (g *Group) SetName(name string) {
+	g.name = name
+}
+
+// SetLabels adds runtime/pprof labels, given as key-value pairs, to every task
+// of g. Along with the labels set here, tasks of a labeled group carry the
+// label "task_index", and "task" if started by GoNamed.
+func (g *Group) SetLabels(labels ...string) {
+	if len(labels)%2 != 0 {
+		panic("errgroup: uneven number of labels")
+	}
+	g.labels = labels
+}
+
+// fail records err if it is the first error of the group and cancels the
+// group.
+func (g *Group) fail(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		if g.cancel != nil {
+			g.cancel()
+		}
+		if g.up != nil && g.policy == FailParent {
+			g.up.fail(err)
+		}
+	})
+}
+
+// recovered records p and returns the error the task fails with.
+func (g *Group) recovered(p *PanicError) error {
+	g.panicked.CompareAndSwap(nil, p)
+	if g.panicHandler != nil {
+		return g.panicHandler(p)
+	}
+	return p
+}
+
+// PanicHandler sets h to be called with every panic recovered from a task.
+// The error returned by h is recorded as the result of the task; returning
+// nil treats the task as successful. Without a handler the *PanicError itself
+// is recorded.
+func (g *Group) PanicHandler(h func(p *PanicError) error) {
+	g.panicHandler = h
+}
+
+// RepanicOnWait makes Wait panic with the first *PanicError recovered from a
+// task, once all tasks have returned. This is mostly useful in tests, where a
+// panic should fail loudly rather than surface as an error.
+func (g *Group) RepanicOnWait() {
+	g.repanic = true
 }
 
 // GOMAXPROCS set max goroutine to work.
+//
+// The limit is kept across Reset, workers are restarted on demand for every
+// batch.
 func (g *Group) GOMAXPROCS(n int) {
 	if n <= 0 {
 		panic("errgroup: GOMAXPROCS must great than 0")
 	}
-	g.workerOnce.Do(func() {
-		g.ch = make(chan func(context.Context) error, n)
-		for i := 0; i < n; i++ {
-			go func() {
-				for f := range g.ch {
-					g.do(f)
-				}
-			}()
-		}
-	})
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.maxprocs == 0 {
+		g.maxprocs = n
+	}
+}
+
+// SetLimiter makes every task of g wait for l before it starts, so tasks are
+// started no faster than l allows. Waiting honors the group context: a task
+// whose wait is canceled does not run and fails with the error of l.Wait.
+//
+// For a GOMAXPROCS limited group the waiting task holds its worker, so both
+// the rate and the concurrency limit apply.
+func (g *Group) SetLimiter(l Limiter) {
+	g.limiter = l
+}
+
+// startWorkers starts the workers of a GOMAXPROCS limited group unless they
+// are already running. g.mu must be held.
+func (g *Group) startWorkers() {
+	if g.exec != nil {
+		return
+	}
+	switch {
+	case g.priority:
+		g.exec = newPriorityExecutor(g.maxprocs, g.aging, g.do)
+	case g.stealing:
+		g.exec = newStealingExecutor(g.maxprocs, g.do)
+	default:
+		g.exec = newChanExecutor(g.maxprocs, g.do)
+	}
 }
 
 // Go calls the given function in a new goroutine.
@@ -88,32 +300,114 @@ func (g *Group) GOMAXPROCS(n int) {
 // The first call to return a non-nil error cancels the group; its error will be
 // returned by Wait.
 func (g *Group) Go(f func(ctx context.Context) error) {
+	g.goTask("", f)
+}
+
+// GoNamed is like Go, but names the task. The task runs under the
+// runtime/pprof label "task" and errors returned by it are wrapped with its
+// name.
+func (g *Group) GoNamed(name string, f func(ctx context.Context) error) {
+	g.goTask(name, f)
+}
+
+func (g *Group) goTask(name string, f func(ctx context.Context) error) {
+	g.submit(&task{f: f, info: TaskInfo{Name: name}})
+}
+
+func (g *Group) submit(t *task) {
 	g.wg.Add(1)
-	if g.ch != nil {
-		select {
-		case g.ch <- f:
-		default:
-			g.chs = append(g.chs, f)
-		}
+	g.counters.submitted.Add(1)
+	g.counters.queued.Add(1)
+	t.info.Index = int(atomic.AddInt64(&g.n, 1) - 1)
+	t.info.Group = g.name
+	g.begin()
+	g.mu.Lock()
+	if g.maxprocs > 0 {
+		g.startWorkers()
+		g.exec.submit(t)
+		g.mu.Unlock()
 		return
 	}
-	go g.do(f)
+	g.mu.Unlock()
+	go g.do(t)
 }
 
 // Wait blocks until all function calls from the Go method have returned, then
 // returns the first non-nil error (if any) from them.
+//
+// Wait also waits for every sub-group created by Sub. It is safe to call Wait
+// more than once and from several goroutines.
 func (g *Group) Wait() error {
-	if g.ch != nil {
-		for _, f := range g.chs {
-			g.ch <- f
-		}
+	g.wait()
+	return g.result()
+}
+
+// wait blocks until all tasks and sub-groups of g have returned.
+func (g *Group) wait() {
+	g.mu.Lock()
+	exec := g.exec
+	g.mu.Unlock()
+	if exec != nil {
+		exec.flush()
 	}
 	g.wg.Wait()
-	if g.ch != nil {
-		close(g.ch) // let all receiver exit
+	for i := 0; ; i++ {
+		g.mu.Lock()
+		if i == len(g.subs) {
+			g.mu.Unlock()
+			break
+		}
+		sub := g.subs[i]
+		g.mu.Unlock()
+		sub.Wait()
+	}
+
+	g.mu.Lock()
+	if g.exec != nil {
+		g.exec.stop()
+		g.exec = nil
 	}
+	g.mu.Unlock()
 	if g.cancel != nil {
 		g.cancel()
 	}
+}
+
+// result returns the outcome of a finished batch, re-panicking if asked to by
+// RepanicOnWait.
+func (g *Group) result() error {
+	if p := g.panicked.Load(); p != nil && g.repanic {
+		panic(p)
+	}
 	return g.err
 }
+
+// Reset prepares g for a new batch of tasks once Wait has returned. The error
+// of the previous batch is forgotten and, for a Group created by WithCancel, a
+// fresh Context is derived from the parent given to WithCancel.
+//
+// Reset must not be called while tasks of the group are still running.
+func (g *Group) Reset() {
+	// Let the background wait of an aborted WaitContext return first, so
+	// that it neither races the next batch on wg nor cancels it.
+	g.mu.Lock()
+	drain := g.drain
+	g.mu.Unlock()
+	if drain != nil {
+		<-drain
+	}
+	g.err = nil
+	g.errOnce = sync.Once{}
+	g.n = 0
+	g.panicked.Store(nil)
+	g.mu.Lock()
+	g.subs = nil
+	g.done = nil
+	g.started = false
+	g.drain = nil
+	g.mu.Unlock()
+	if g.cancel != nil {
+		g.cancel()
+		g.ctx, g.cancel = context.WithCancel(g.parent)
+	}
+}
@@ -0,0 +1,60 @@
+package gosync
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+// spin burns roughly n iterations of CPU, standing in for the work of a task.
+func spin(n int) int {
+	x := 0
+	for i := 0; i < n; i++ {
+		x += i ^ x
+	}
+	return x
+}
+
+var spinSink int64
+
+func benchmarkExecutor(b *testing.B, setup func(g *Group), work int) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var g Group
+		g.GOMAXPROCS(runtime.GOMAXPROCS(0))
+		setup(&g)
+		for j := 0; j < 1000; j++ {
+			g.Go(func(context.Context) error {
+				if spin(work) == -1 {
+					spinSink++
+				}
+				return nil
+			})
+		}
+		g.Wait()
+	}
+}
+
+func BenchmarkGroupExecutor(b *testing.B) {
+	executors := []struct {
+		name  string
+		setup func(g *Group)
+	}{
+		{"chan", func(*Group) {}},
+		{"stealing", (*Group).WorkStealing},
+	}
+	sizes := []struct {
+		name string
+		work int
+	}{
+		{"small", 10},
+		{"large", 10_000},
+	}
+	for _, e := range executors {
+		for _, s := range sizes {
+			b.Run(e.name+"/"+s.name, func(b *testing.B) {
+				benchmarkExecutor(b, e.setup, s.work)
+			})
+		}
+	}
+}
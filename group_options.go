@@ -0,0 +1,126 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultTaskBackoff    = 10 * time.Millisecond
+	defaultTaskMaxBackoff = time.Second
+)
+
+// TaskOption configures a task started by GoWithOptions.
+type TaskOption func(*taskOptions)
+
+type taskOptions struct {
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	retryable  func(error) bool
+}
+
+// TaskTimeout bounds every attempt of the task by d. The deadline is derived
+// from the group context, so canceling the group still cancels the attempt.
+func TaskTimeout(d time.Duration) TaskOption {
+	return func(o *taskOptions) {
+		o.timeout = d
+	}
+}
+
+// TaskRetries retries a failed task up to n more times.
+func TaskRetries(n int) TaskOption {
+	return func(o *taskOptions) {
+		o.retries = n
+	}
+}
+
+// TaskBackoff sets the delay before the first retry. The delay doubles on
+// every further retry up to maxDelay, and is jittered to avoid retries of many
+// tasks hitting a backend at the same time.
+func TaskBackoff(base, maxDelay time.Duration) TaskOption {
+	return func(o *taskOptions) {
+		o.backoff = base
+		o.maxBackoff = maxDelay
+	}
+}
+
+// TaskRetryIf restricts retries to errors for which retryable returns true.
+// By default every error is retried.
+func TaskRetryIf(retryable func(error) bool) TaskOption {
+	return func(o *taskOptions) {
+		o.retryable = retryable
+	}
+}
+
+// GoWithOptions is like Go, but runs f according to opts.
+//
+// If f is retried and no attempt succeeds, the error returned to the group
+// wraps the errors of all attempts.
+func (g *Group) GoWithOptions(f func(ctx context.Context) error, opts ...TaskOption) {
+	o := taskOptions{
+		backoff:    defaultTaskBackoff,
+		maxBackoff: defaultTaskMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	g.Go(func(ctx context.Context) error {
+		return o.run(ctx, f)
+	})
+}
+
+func (o *taskOptions) run(ctx context.Context, f func(ctx context.Context) error) error {
+	var errs []error
+	backoff := o.backoff
+	for attempt := 0; ; attempt++ {
+		err := o.attempt(ctx, f)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if attempt >= o.retries || (o.retryable != nil && !o.retryable(err)) {
+			return retryError(attempt+1, errs)
+		}
+		timer := time.NewTimer(jitter(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retryError(attempt+1, append(errs, ctx.Err()))
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > o.maxBackoff {
+			backoff = o.maxBackoff
+		}
+	}
+}
+
+func (o *taskOptions) attempt(ctx context.Context, f func(ctx context.Context) error) error {
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
+
+// jitter returns a random duration in [d/2, d].
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryError reports the errors of all attempts of a task. A task that was
+// attempted once returns its error unchanged.
+func retryError(attempts int, errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return fmt.Errorf("errgroup: task failed after %d attempts: %w", attempts, errors.Join(errs...))
+}
@@ -0,0 +1,106 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGoWithOptionsRetry(t *testing.T) {
+	var (
+		g     Group
+		calls int32
+	)
+	g.GoWithOptions(func(context.Context) error {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, TaskRetries(5), TaskBackoff(time.Millisecond, 4*time.Millisecond))
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	if calls != 3 {
+		t.Fatalf("task called %d times; want 3", calls)
+	}
+}
+
+func TestGoWithOptionsAllAttemptsFail(t *testing.T) {
+	var (
+		g     Group
+		calls int32
+	)
+	errs := []error{errors.New("a"), errors.New("b"), errors.New("c")}
+	g.GoWithOptions(func(context.Context) error {
+		return errs[atomic.AddInt32(&calls, 1)-1]
+	}, TaskRetries(2), TaskBackoff(time.Millisecond, time.Millisecond))
+	err := g.Wait()
+	for _, want := range errs {
+		if !errors.Is(err, want) {
+			t.Errorf("Wait() = %v; want it to wrap %v", err, want)
+		}
+	}
+	if !strings.Contains(err.Error(), "3 attempts") {
+		t.Errorf("Wait() = %v; want it to report 3 attempts", err)
+	}
+}
+
+func TestGoWithOptionsRetryIf(t *testing.T) {
+	var (
+		g     Group
+		calls int32
+	)
+	permanent := errors.New("permanent")
+	g.GoWithOptions(func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return permanent
+	}, TaskRetries(5), TaskRetryIf(func(err error) bool { return err != permanent }))
+	if err := g.Wait(); err != permanent {
+		t.Fatalf("Wait() = %v; want %v", err, permanent)
+	}
+	if calls != 1 {
+		t.Fatalf("task called %d times; want 1", calls)
+	}
+}
+
+func TestGoWithOptionsTimeout(t *testing.T) {
+	var (
+		g     Group
+		calls int32
+	)
+	g.GoWithOptions(func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, TaskTimeout(10*time.Millisecond), TaskRetries(1), TaskBackoff(time.Millisecond, time.Millisecond))
+	err := g.Wait()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if calls != 2 {
+		t.Fatalf("task called %d times; want 2", calls)
+	}
+}
+
+func TestGoWithOptionsGroupCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := WithCancel(ctx)
+	g.GoWithOptions(func(context.Context) error {
+		cancel()
+		return errors.New("fail")
+	}, TaskRetries(10), TaskBackoff(time.Hour, time.Hour))
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wait() = %v; want %v", err, context.Canceled)
+		}
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for the backoff to honor the group context")
+	}
+}
@@ -0,0 +1,123 @@
+package gosync
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// PriorityScheduling makes the workers of a GOMAXPROCS limited group always
+// pick the pending task with the highest priority, as given to GoPriority,
+// instead of running tasks in submission order. Tasks of equal priority run
+// in submission order.
+//
+// To prevent starvation, a pending task gains one priority level for every
+// aging it spends waiting. An aging of 0 disables aging.
+//
+// PriorityScheduling must be called before the first call to Go. It has no
+// effect on a group without GOMAXPROCS limit, where every task starts
+// immediately.
+func (g *Group) PriorityScheduling(aging time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.priority = true
+	g.stealing = false
+	g.aging = aging
+}
+
+// GoPriority is like Go, but queues f with the given priority. Higher values
+// run first. Go queues tasks with priority 0.
+func (g *Group) GoPriority(priority int, f func(ctx context.Context) error) {
+	g.submit(&task{f: f, priority: priority})
+}
+
+// priorityExecutor keeps pending tasks in a heap ordered by their aged
+// priority.
+type priorityExecutor struct {
+	run   func(t *task)
+	aging time.Duration
+	start time.Time
+
+	mu      sync.Mutex
+	cond    sync.Cond
+	queue   taskHeap
+	seq     uint64
+	stopped bool
+}
+
+func newPriorityExecutor(workers int, aging time.Duration, run func(t *task)) *priorityExecutor {
+	e := &priorityExecutor{run: run, aging: aging, start: time.Now()}
+	e.cond.L = &e.mu
+	for i := 0; i < workers; i++ {
+		go e.worker()
+	}
+	return e
+}
+
+func (e *priorityExecutor) submit(t *task) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t.seq = e.seq
+	e.seq++
+	// The aged priority of a task waiting since at is
+	// priority + (now-at)/aging. Every pending task ages at the same rate,
+	// so ordering by priority*aging - at is stable over time.
+	t.score = int64(t.priority)
+	if e.aging > 0 {
+		t.score = int64(t.priority)*int64(e.aging) - int64(time.Since(e.start))
+	}
+	heap.Push(&e.queue, t)
+	e.cond.Signal()
+}
+
+func (e *priorityExecutor) flush() {}
+
+func (e *priorityExecutor) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	e.cond.Broadcast()
+}
+
+func (e *priorityExecutor) worker() {
+	for {
+		e.mu.Lock()
+		for len(e.queue) == 0 && !e.stopped {
+			e.cond.Wait()
+		}
+		if len(e.queue) == 0 {
+			e.mu.Unlock()
+			return
+		}
+		t := heap.Pop(&e.queue).(*task)
+		e.mu.Unlock()
+		e.run(t)
+	}
+}
+
+// taskHeap implements heap.Interface, popping the task with the highest score
+// first.
+type taskHeap []*task
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score > h[j].score
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x any) { *h = append(*h, x.(*task)) }
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return t
+}
@@ -0,0 +1,97 @@
+package gosync
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runPriorityOrder runs the given tasks on a single worker, which is held
+// busy until all of them are queued, and returns the order they ran in.
+func runPriorityOrder(t *testing.T, g *Group, submit func(record func(id int) func(context.Context) error)) []int {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	record := func(id int) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, id)
+			return nil
+		}
+	}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	g.GoPriority(1<<20, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	submit(record)
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	return order
+}
+
+func TestPriorityScheduling(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(1)
+	g.PriorityScheduling(0)
+	order := runPriorityOrder(t, &g, func(record func(int) func(context.Context) error) {
+		g.GoPriority(1, record(1))
+		g.Go(record(2))
+		g.GoPriority(5, record(3))
+		g.GoPriority(1, record(4))
+		g.GoPriority(-1, record(5))
+		g.GoPriority(5, record(6))
+	})
+	if want := []int{3, 6, 1, 4, 2, 5}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("tasks ran in order %v; want %v", order, want)
+	}
+}
+
+func TestPrioritySchedulingAging(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(1)
+	g.PriorityScheduling(time.Millisecond)
+	order := runPriorityOrder(t, &g, func(record func(int) func(context.Context) error) {
+		g.GoPriority(0, record(1))
+		// By now the first task has aged well past priority 5.
+		time.Sleep(50 * time.Millisecond)
+		g.GoPriority(5, record(2))
+		g.GoPriority(1000, record(3))
+	})
+	if want := []int{3, 1, 2}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("tasks ran in order %v; want %v", order, want)
+	}
+}
+
+func TestPrioritySchedulingReuse(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(2)
+	g.PriorityScheduling(time.Millisecond)
+	for batch := 0; batch < 3; batch++ {
+		var mu sync.Mutex
+		n := 0
+		for i := 0; i < 100; i++ {
+			g.GoPriority(i%7, func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				n++
+				return nil
+			})
+		}
+		g.Wait()
+		g.Reset()
+		if n != 100 {
+			t.Fatalf("batch %d ran %d tasks; want 100", batch, n)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gosync
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// GroupStats is a snapshot of the task counters of a Group. The counters
+// accumulate over the lifetime of the Group and are not cleared by Reset.
+type GroupStats struct {
+	// Submitted is the number of tasks passed to the group.
+	Submitted int64
+	// Queued is the number of tasks waiting for a worker.
+	Queued int64
+	// Running is the number of tasks currently running.
+	Running int64
+	// Succeeded is the number of tasks that returned nil.
+	Succeeded int64
+	// Failed is the number of tasks that returned an error.
+	Failed int64
+	// Panicked is the number of tasks that panicked.
+	Panicked int64
+}
+
+// GroupHooks are callbacks invoked around every task of a Group. Hooks run on
+// the goroutine of the task and must be safe for concurrent use.
+type GroupHooks struct {
+	// OnStart is called right before a task starts running.
+	OnStart func(t TaskInfo)
+	// OnFinish is called once a task has returned, with its running time
+	// and the error recorded for it. A task that never ran, because waiting
+	// for the limiter of the group failed, is reported with a zero running
+	// time and the error of the limiter, without a call to OnStart.
+	OnFinish func(t TaskInfo, d time.Duration, err error)
+}
+
+type groupCounters struct {
+	submitted atomic.Int64
+	queued    atomic.Int64
+	running   atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+}
+
+// Stats returns a snapshot of the task counters of g.
+func (g *Group) Stats() GroupStats {
+	return GroupStats{
+		Submitted: g.counters.submitted.Load(),
+		Queued:    g.counters.queued.Load(),
+		Running:   g.counters.running.Load(),
+		Succeeded: g.counters.succeeded.Load(),
+		Failed:    g.counters.failed.Load(),
+		Panicked:  g.counters.panicked.Load(),
+	}
+}
+
+// SetHooks installs h on g. It must be called before the first call to Go.
+// Unset hooks cost nothing, not even a clock read.
+func (g *Group) SetHooks(h GroupHooks) {
+	g.hooks = h
+}
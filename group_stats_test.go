@@ -0,0 +1,71 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupStats(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	g.Go(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	g.Go(func(context.Context) error { return errors.New("fail") })
+	g.Go(func(context.Context) error { panic("boom") })
+	<-started
+
+	if got, want := g.Stats(), (GroupStats{Submitted: 3, Queued: 2, Running: 1}); got != want {
+		t.Errorf("Stats() while running = %+v; want %+v", got, want)
+	}
+	close(release)
+	g.Wait()
+	if got, want := g.Stats(), (GroupStats{Submitted: 3, Succeeded: 1, Failed: 1, Panicked: 1}); got != want {
+		t.Errorf("Stats() after Wait = %+v; want %+v", got, want)
+	}
+}
+
+func TestGroupHooks(t *testing.T) {
+	var (
+		g        Group
+		mu       sync.Mutex
+		started  = map[int]bool{}
+		finished = map[int]error{}
+	)
+	boom := errors.New("boom")
+	g.SetHooks(GroupHooks{
+		OnStart: func(ti TaskInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			started[ti.Index] = true
+		},
+		OnFinish: func(ti TaskInfo, d time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if d < 10*time.Millisecond && err == nil {
+				t.Errorf("task %d ran for %v; want at least 10ms", ti.Index, d)
+			}
+			finished[ti.Index] = err
+		},
+	})
+	g.Go(func(context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	g.Go(func(context.Context) error { return boom })
+	g.Wait()
+
+	if len(started) != 2 || len(finished) != 2 {
+		t.Fatalf("hooks saw started=%v finished=%v; want 2 tasks each", started, finished)
+	}
+	if finished[0] != nil || finished[1] != boom {
+		t.Errorf("OnFinish errors = %v; want [nil %v]", finished, boom)
+	}
+}
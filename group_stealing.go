@@ -0,0 +1,137 @@
+package gosync
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// WorkStealing makes a GOMAXPROCS limited group run its tasks on a
+// work-stealing executor instead of a shared channel: every worker has its own
+// queue, and idle workers steal from the queues of their peers. This avoids
+// contention on a single channel when a group runs many small tasks.
+//
+// WorkStealing must be called before the first call to Go, and replaces
+// PriorityScheduling. Tasks are not run in submission order.
+func (g *Group) WorkStealing() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stealing = true
+	g.priority = false
+}
+
+// stealingExecutor distributes tasks round-robin over per-worker queues. A
+// worker takes tasks from its own queue and, once that is empty, steals from
+// the queues of the others. Every queue is FIFO, for the owner and the
+// thieves alike: tasks only ever enter through submit, so running the newest
+// task of a queue first would not buy any locality, and would leave early
+// tasks waiting until the end of a long batch.
+type stealingExecutor struct {
+	run    func(t *task)
+	queues []taskQueue
+	next   atomic.Uint32
+
+	// pending counts tasks queued in any queue. Workers finding nothing
+	// to do sleep on cond until pending becomes non-zero.
+	pending atomic.Int64
+	idle    atomic.Int32
+	mu      sync.Mutex
+	cond    sync.Cond
+	stopped bool
+}
+
+func newStealingExecutor(workers int, run func(t *task)) *stealingExecutor {
+	e := &stealingExecutor{run: run, queues: make([]taskQueue, workers)}
+	e.cond.L = &e.mu
+	for i := 0; i < workers; i++ {
+		go e.worker(i)
+	}
+	return e
+}
+
+func (e *stealingExecutor) submit(t *task) {
+	i := int(e.next.Add(1) % uint32(len(e.queues)))
+	e.queues[i].push(t)
+	e.pending.Add(1)
+	// Pairs with the idle/pending check in worker: either the worker sees
+	// the task, or we see the worker going idle and wake it up.
+	if e.idle.Load() > 0 {
+		e.mu.Lock()
+		e.cond.Signal()
+		e.mu.Unlock()
+	}
+}
+
+func (e *stealingExecutor) flush() {}
+
+func (e *stealingExecutor) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	e.cond.Broadcast()
+}
+
+func (e *stealingExecutor) worker(id int) {
+	for {
+		if t := e.find(id); t != nil {
+			e.pending.Add(-1)
+			e.run(t)
+			continue
+		}
+		e.mu.Lock()
+		e.idle.Add(1)
+		for e.pending.Load() == 0 && !e.stopped {
+			e.cond.Wait()
+		}
+		e.idle.Add(-1)
+		stopped := e.stopped && e.pending.Load() == 0
+		e.mu.Unlock()
+		if stopped {
+			return
+		}
+	}
+}
+
+// find takes a task from the queue of worker id, or steals one from a peer.
+func (e *stealingExecutor) find(id int) *task {
+	if t := e.queues[id].pop(); t != nil {
+		return t
+	}
+	for i := 1; i < len(e.queues); i++ {
+		if t := e.queues[(id+i)%len(e.queues)].pop(); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// taskQueue is a FIFO queue of tasks. It is padded to keep the locks of
+// neighboring queues on separate cache lines.
+type taskQueue struct {
+	mu    sync.Mutex
+	tasks []*task
+	head  int
+	_     [24]byte
+}
+
+func (q *taskQueue) push(t *task) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.head == len(q.tasks) {
+		// Empty: reuse the backing array from the start.
+		q.tasks, q.head = q.tasks[:0], 0
+	}
+	q.tasks = append(q.tasks, t)
+}
+
+// pop takes the earliest task of q, or returns nil.
+func (q *taskQueue) pop() *task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.head == len(q.tasks) {
+		return nil
+	}
+	t := q.tasks[q.head]
+	q.tasks[q.head] = nil
+	q.head++
+	return t
+}
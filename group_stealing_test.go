@@ -0,0 +1,115 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWorkStealing(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(4)
+	g.WorkStealing()
+	for batch := 0; batch < 3; batch++ {
+		var (
+			n       int64
+			running int64
+			peak    int64
+		)
+		for i := 0; i < 10_000; i++ {
+			g.Go(func(context.Context) error {
+				r := atomic.AddInt64(&running, 1)
+				for {
+					p := atomic.LoadInt64(&peak)
+					if r <= p || atomic.CompareAndSwapInt64(&peak, p, r) {
+						break
+					}
+				}
+				atomic.AddInt64(&n, 1)
+				atomic.AddInt64(&running, -1)
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatalf("batch %d: Wait() = %v; want nil", batch, err)
+		}
+		if n != 10_000 {
+			t.Fatalf("batch %d: ran %d tasks; want 10000", batch, n)
+		}
+		if peak > 4 {
+			t.Fatalf("batch %d: %d tasks ran concurrently; want at most 4", batch, peak)
+		}
+		g.Reset()
+	}
+}
+
+func TestWorkStealingError(t *testing.T) {
+	g := WithCancel(context.Background())
+	g.GOMAXPROCS(2)
+	g.WorkStealing()
+	boom := errors.New("boom")
+	for i := 0; i < 100; i++ {
+		i := i
+		g.Go(func(ctx context.Context) error {
+			if i == 10 {
+				return boom
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != boom {
+		t.Fatalf("Wait() = %v; want %v", err, boom)
+	}
+}
+
+func TestTaskQueue(t *testing.T) {
+	var q taskQueue
+	tasks := make([]*task, 4)
+	for i := range tasks {
+		tasks[i] = &task{info: TaskInfo{Index: i}}
+		q.push(tasks[i])
+	}
+	for i := range tasks {
+		if got := q.pop(); got != tasks[i] {
+			t.Fatalf("pop() = %v; want task %d", got, i)
+		}
+	}
+	if got := q.pop(); got != nil {
+		t.Fatalf("pop() on empty queue = task %d; want nil", got.info.Index)
+	}
+	q.push(tasks[0])
+	if got := q.pop(); got != tasks[0] {
+		t.Fatalf("pop() after reuse = %v; want task 0", got)
+	}
+}
+
+// TestWorkStealingOrder makes sure that a single worker runs its tasks in
+// submission order.
+func TestWorkStealingOrder(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(1)
+	g.WorkStealing()
+	release := make(chan struct{})
+	var order []int
+	g.Go(func(context.Context) error {
+		<-release
+		return nil
+	})
+	for i := 0; i < 10; i++ {
+		i := i
+		g.Go(func(context.Context) error {
+			order = append(order, i)
+			return nil
+		})
+	}
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("tasks ran in order %v; want submission order", order)
+		}
+	}
+}
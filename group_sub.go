@@ -0,0 +1,38 @@
+package gosync
+
+import "context"
+
+// SubPolicy controls how the errors of a sub-group reach its parent.
+type SubPolicy int
+
+const (
+	// FailParent records the first error of the sub-group in the parent
+	// as well, which cancels the parent and every other sub-group of it.
+	FailParent SubPolicy = iota
+	// Isolate keeps the errors of the sub-group to itself; they are only
+	// returned by the Wait of the sub-group.
+	Isolate
+)
+
+// Sub returns a child group of g. The context of the child is derived from
+// the context of g, so canceling g cancels the whole tree below it, and the
+// Wait of g also waits for the child to drain. Errors of the child reach g
+// according to policy.
+//
+// Sub-groups belong to the current batch of g and are forgotten by Reset.
+// The child does not inherit the GOMAXPROCS limit, hooks or panic handling of
+// g.
+func (g *Group) Sub(policy SubPolicy) *Group {
+	parent := g.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	sub := WithCancel(parent)
+	sub.up = g
+	sub.policy = policy
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.subs = append(g.subs, sub)
+	return sub
+}
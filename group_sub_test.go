@@ -0,0 +1,103 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubFailParent(t *testing.T) {
+	g := WithCancel(context.Background())
+	boom := errors.New("boom")
+
+	var siblingCanceled, parentCanceled int32
+	sub := g.Sub(FailParent)
+	sibling := g.Sub(Isolate)
+	sibling.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.StoreInt32(&siblingCanceled, 1)
+		return nil
+	})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.StoreInt32(&parentCanceled, 1)
+		return nil
+	})
+	sub.Go(func(context.Context) error { return boom })
+
+	if err := g.Wait(); err != boom {
+		t.Fatalf("parent Wait() = %v; want %v", err, boom)
+	}
+	if atomic.LoadInt32(&parentCanceled) != 1 || atomic.LoadInt32(&siblingCanceled) != 1 {
+		t.Fatal("error in sub-group did not cancel the whole tree")
+	}
+	if err := sub.Wait(); err != boom {
+		t.Fatalf("sub Wait() = %v; want %v", err, boom)
+	}
+}
+
+func TestSubIsolate(t *testing.T) {
+	g := WithCancel(context.Background())
+	boom := errors.New("boom")
+
+	sub := g.Sub(Isolate)
+	sub.Go(func(context.Context) error { return boom })
+	g.Go(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+			return nil
+		}
+	})
+	if err := g.Wait(); err != nil {
+		t.Fatalf("parent Wait() = %v; want nil", err)
+	}
+	if err := sub.Wait(); err != boom {
+		t.Fatalf("sub Wait() = %v; want %v", err, boom)
+	}
+}
+
+func TestSubWaitedByParent(t *testing.T) {
+	var (
+		g    Group
+		done int32
+	)
+	// A stage spawning its own workers from within a task of the parent.
+	g.Go(func(context.Context) error {
+		stage := g.Sub(FailParent)
+		for i := 0; i < 3; i++ {
+			stage.Go(func(context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&done, 1)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	if n := atomic.LoadInt32(&done); n != 3 {
+		t.Fatalf("parent Wait() returned after %d sub-group tasks; want 3", n)
+	}
+}
+
+func TestSubParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := WithCancel(ctx)
+	sub := g.Sub(Isolate).Sub(Isolate)
+	sub.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	cancel()
+	if err := g.Wait(); err != nil {
+		t.Fatalf("parent Wait() = %v; want nil", err)
+	}
+	if err := sub.Wait(); err != context.Canceled {
+		t.Fatalf("sub Wait() = %v; want %v", err, context.Canceled)
+	}
+}
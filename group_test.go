@@ -7,6 +7,9 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -264,3 +267,154 @@ func TestWithCancel(t *testing.T) {
 		t.Error("error should be Canceled")
 	}
 }
+
+func TestGroupReuse(t *testing.T) {
+	g := WithCancel(context.Background())
+	g.GOMAXPROCS(2)
+	for batch := 0; batch < 3; batch++ {
+		var n int32
+		for i := 0; i < 10; i++ {
+			g.Go(func(ctx context.Context) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				atomic.AddInt32(&n, 1)
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatalf("batch %d: Wait() = %v; want nil", batch, err)
+		}
+		if n != 10 {
+			t.Fatalf("batch %d: ran %d tasks; want 10", batch, n)
+		}
+
+		g.Reset()
+		g.Go(func(context.Context) error { return fmt.Errorf("batch %d", batch) })
+		if err := g.Wait(); err == nil {
+			t.Fatalf("batch %d: Wait() = nil; want error", batch)
+		}
+		g.Reset()
+	}
+}
+
+func TestGroupGoAfterWait(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(1)
+	g.Go(func(context.Context) error { return nil })
+	g.Wait()
+
+	// Go after Wait without Reset must not panic, and must keep the error
+	// of the previous batch.
+	boom := errors.New("boom")
+	g.Go(func(context.Context) error { return boom })
+	if err := g.Wait(); err != boom {
+		t.Fatalf("Wait() = %v; want %v", err, boom)
+	}
+	g.Go(func(context.Context) error { return errors.New("ignored") })
+	if err := g.Wait(); err != boom {
+		t.Fatalf("Wait() = %v; want %v", err, boom)
+	}
+	g.Reset()
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() after Reset = %v; want nil", err)
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	var g Group
+	g.Go(func(context.Context) error { return nil })
+	g.Go(func(context.Context) error { panic("2233") })
+	err := g.Wait()
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Wait() = %v; want a *PanicError", err)
+	}
+	if pe.Value != "2233" {
+		t.Errorf("PanicError.Value = %v; want %q", pe.Value, "2233")
+	}
+	if pe.Task.Index != 1 {
+		t.Errorf("PanicError.Task.Index = %d; want 1", pe.Task.Index)
+	}
+	if len(pe.Stack) == 0 {
+		t.Error("PanicError.Stack is empty")
+	}
+
+	boom := errors.New("boom")
+	g.Reset()
+	g.Go(func(context.Context) error { panic(boom) })
+	if err := g.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait() = %v; want it to wrap %v", err, boom)
+	}
+}
+
+func TestPanicHandler(t *testing.T) {
+	var (
+		g      Group
+		values []any
+	)
+	g.GOMAXPROCS(1)
+	g.PanicHandler(func(p *PanicError) error {
+		values = append(values, p.Value)
+		return nil
+	})
+	g.Go(func(context.Context) error { panic(1) })
+	g.Go(func(context.Context) error { panic(2) })
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("handler saw %v; want 2 panics", values)
+	}
+}
+
+func TestRepanicOnWait(t *testing.T) {
+	var g Group
+	g.RepanicOnWait()
+	g.Go(func(context.Context) error { panic("2233") })
+	defer func() {
+		pe, ok := recover().(*PanicError)
+		if !ok || pe.Value != "2233" {
+			t.Fatalf("Wait() panicked with %v; want a *PanicError for 2233", pe)
+		}
+	}()
+	g.Wait()
+	t.Fatal("Wait() did not panic")
+}
+
+func TestGoNamed(t *testing.T) {
+	var g Group
+	g.SetName("crawler")
+	g.SetLabels("job", "nightly")
+
+	type labels struct{ group, task, index, job string }
+	got := make(chan labels, 1)
+	g.GoNamed("fetch", func(ctx context.Context) error {
+		var l labels
+		l.group, _ = pprof.Label(ctx, "group")
+		l.task, _ = pprof.Label(ctx, "task")
+		l.index, _ = pprof.Label(ctx, "task_index")
+		l.job, _ = pprof.Label(ctx, "job")
+		got <- l
+		return nil
+	})
+	boom := errors.New("boom")
+	g.GoNamed("store", func(context.Context) error { return boom })
+	err := g.Wait()
+	if l, want := <-got, (labels{"crawler", "fetch", "0", "nightly"}); l != want {
+		t.Errorf("pprof labels = %+v; want %+v", l, want)
+	}
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), `crawler: task "store" (1)`) {
+		t.Errorf("Wait() = %v; want the error wrapped with the task name", err)
+	}
+
+	g.Reset()
+	g.GoNamed("parse", func(context.Context) error { panic("2233") })
+	var pe *PanicError
+	if err := g.Wait(); !errors.As(err, &pe) {
+		t.Fatalf("Wait() = %v; want a *PanicError", err)
+	}
+	if want := (TaskInfo{Index: 0, Name: "parse", Group: "crawler"}); pe.Task != want {
+		t.Errorf("PanicError.Task = %+v; want %+v", pe.Task, want)
+	}
+}
@@ -0,0 +1,110 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrWaitAborted is returned by WaitContext and WaitTimeout when they give up
+// before all tasks of the group have finished.
+var ErrWaitAborted = errors.New("errgroup: wait aborted before all tasks finished")
+
+// Done returns a channel that is closed once all tasks of the current batch,
+// and of its sub-groups, have finished. Done only watches the group: unlike
+// Wait it neither cancels the group context nor stops its workers, so tasks
+// may still be passed to Go afterwards.
+//
+// If Done is called before the first task of the batch is started, the
+// channel is closed once the tasks started after it have finished. A channel
+// that was closed is not reopened by later calls to Go; call Done again to
+// watch them.
+//
+// After WaitContext gave up, the channel is only closed once the wait it left
+// running in the background has returned, so that the group can be Reset as
+// soon as Done is closed.
+func (g *Group) Done() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done == nil {
+		g.done = make(chan struct{})
+		if g.started && g.active == 0 && !g.draining {
+			done := g.done
+			g.done = nil
+			close(done)
+			return done
+		}
+	}
+	return g.done
+}
+
+// begin counts a new task of g, and of the groups above it, for Done.
+func (g *Group) begin() {
+	for p := g; p != nil; p = p.up {
+		p.mu.Lock()
+		p.active++
+		p.started = true
+		p.mu.Unlock()
+	}
+}
+
+// end counts a finished task of g and of the groups above it, closing the
+// channel returned by Done of the groups left without tasks.
+func (g *Group) end() {
+	for p := g; p != nil; p = p.up {
+		p.mu.Lock()
+		if p.active--; p.active == 0 && p.done != nil && !p.draining {
+			close(p.done)
+			p.done = nil
+		}
+		p.mu.Unlock()
+	}
+}
+
+// WaitContext is like Wait, but returns early if ctx is done before all tasks
+// have finished. The error returned then wraps both ErrWaitAborted and
+// ctx.Err(); the tasks keep running and the group keeps draining in the
+// background, which can be observed with Done.
+//
+// Like Wait, WaitContext cancels the group context once all tasks have
+// finished, so no more tasks may be passed to Go afterwards. Calls to
+// WaitContext share a single background wait per batch.
+func (g *Group) WaitContext(ctx context.Context) error {
+	g.mu.Lock()
+	drain := g.drain
+	if drain == nil {
+		drain = make(chan struct{})
+		g.drain = drain
+		g.draining = true
+		go g.drainBatch(drain)
+	}
+	g.mu.Unlock()
+	select {
+	case <-drain:
+		return g.result()
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrWaitAborted, ctx.Err())
+	}
+}
+
+// drainBatch waits for the current batch in the background for WaitContext,
+// then closes drain and the channel returned by Done.
+func (g *Group) drainBatch(drain chan struct{}) {
+	g.wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.draining = false
+	close(drain)
+	if g.active == 0 && g.done != nil {
+		close(g.done)
+		g.done = nil
+	}
+}
+
+// WaitTimeout is like WaitContext, giving up after d.
+func (g *Group) WaitTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return g.WaitContext(ctx)
+}
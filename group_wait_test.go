@@ -0,0 +1,168 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	var g Group
+	release := make(chan struct{})
+	g.Go(func(context.Context) error {
+		<-release // ignores cancellation
+		return nil
+	})
+
+	err := g.WaitTimeout(defaultTestShortTimeout)
+	if !errors.Is(err, ErrWaitAborted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitTimeout() = %v; want %v wrapping %v", err, ErrWaitAborted, context.DeadlineExceeded)
+	}
+	select {
+	case <-g.Done():
+		t.Fatal("Done() closed while a task is still running")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-g.Done():
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Done() to close")
+	}
+	if err := g.WaitTimeout(defaultTestTimeout); err != nil {
+		t.Fatalf("WaitTimeout() after Done = %v; want nil", err)
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	g := WithCancel(context.Background())
+	g.GOMAXPROCS(2)
+	boom := errors.New("boom")
+	for i := 0; i < 5; i++ {
+		g.Go(func(context.Context) error { return nil })
+	}
+	g.Go(func(context.Context) error { return boom })
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTestTimeout)
+	defer cancel()
+	if err := g.WaitContext(ctx); err != boom {
+		t.Fatalf("WaitContext() = %v; want %v", err, boom)
+	}
+	if err := g.Wait(); err != boom {
+		t.Fatalf("Wait() = %v; want %v", err, boom)
+	}
+
+	g.Reset()
+	g.Go(func(context.Context) error { return nil })
+	if err := g.WaitContext(ctx); err != nil {
+		t.Fatalf("WaitContext() after Reset = %v; want nil", err)
+	}
+}
+
+func TestWaitContextCanceled(t *testing.T) {
+	var g Group
+	release := make(chan struct{})
+	defer close(release)
+	g.Go(func(context.Context) error {
+		<-release
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.WaitContext(ctx); !errors.Is(err, ErrWaitAborted) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("WaitContext() = %v; want %v wrapping %v", err, ErrWaitAborted, context.Canceled)
+	}
+}
+
+func TestDoneBeforeGo(t *testing.T) {
+	g := WithCancel(context.Background())
+	g.GOMAXPROCS(1)
+	done := g.Done()
+
+	release := make(chan struct{})
+	ctxErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		g.Go(func(ctx context.Context) error {
+			<-release
+			ctxErrs <- ctx.Err()
+			return nil
+		})
+		// Done between two calls to Go must not end the batch either.
+		g.Done()
+	}
+	select {
+	case <-done:
+		t.Fatal("Done() closed while tasks are still running")
+	case <-time.After(defaultTestShortTimeout):
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Done() to close")
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-ctxErrs; err != nil {
+			t.Fatalf("task context = %v; want it live until Wait", err)
+		}
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+}
+
+func TestDoneSub(t *testing.T) {
+	var g Group
+	sub := g.Sub(Isolate)
+	release := make(chan struct{})
+	sub.Go(func(context.Context) error {
+		<-release
+		return nil
+	})
+	done := g.Done()
+	select {
+	case <-done:
+		t.Fatal("Done() closed while a task of a sub-group is still running")
+	case <-time.After(defaultTestShortTimeout):
+	}
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Done() to close")
+	}
+}
+
+// TestWaitTimeoutReset aborts WaitTimeout, waits for the batch with Done and
+// starts the next batch right away, which must not race the background wait
+// left behind by WaitTimeout.
+func TestWaitTimeoutReset(t *testing.T) {
+	g := WithCancel(context.Background())
+	g.GOMAXPROCS(2)
+	for i := 0; i < 200; i++ {
+		release := make(chan struct{})
+		g.Go(func(context.Context) error {
+			<-release
+			return nil
+		})
+		if err := g.WaitTimeout(0); !errors.Is(err, ErrWaitAborted) {
+			t.Fatalf("WaitTimeout() = %v; want %v", err, ErrWaitAborted)
+		}
+		close(release)
+		<-g.Done()
+		g.Reset()
+
+		g.Go(func(ctx context.Context) error {
+			// The background wait of the previous batch must not
+			// cancel this one.
+			return ctx.Err()
+		})
+		if err := g.Wait(); err != nil {
+			t.Fatalf("Wait() after Reset = %v; want nil", err)
+		}
+		g.Reset()
+	}
+}
@@ -0,0 +1,83 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Hedge calls f and, if it has not succeeded after delay, calls it again
+// concurrently, up to maxAttempts attempts in total. An attempt failing early
+// starts the next one right away. The first successful attempt wins: Hedge
+// returns its result along with its index, starting at 0, which tells how
+// much hedging was needed.
+//
+// Attempts run in a group created by WithCancel(ctx). Once an attempt won, the
+// others are canceled through their context; Hedge does not wait for them to
+// return. A panicking attempt fails with a *PanicError, like any other failed
+// attempt, without canceling the others. If no attempt succeeds, the error
+// wraps the errors of all attempts and the returned index is -1.
+func Hedge[T any](ctx context.Context, delay time.Duration, maxAttempts int, f func(ctx context.Context) (T, error)) (T, int, error) {
+	if maxAttempts <= 0 {
+		panic("errgroup: Hedge needs at least one attempt")
+	}
+	type result struct {
+		v       T
+		attempt int
+		err     error
+	}
+	g := WithCancel(ctx)
+	// Buffered so that losing attempts never block.
+	results := make(chan result, maxAttempts)
+	// Attempts are the only tasks of g, so the index of a task is the index
+	// of its attempt.
+	g.PanicHandler(func(p *PanicError) error {
+		results <- result{attempt: p.Task.Index, err: p}
+		return nil
+	})
+	launched := 0
+	launch := func() {
+		attempt := launched
+		launched++
+		g.Go(func(ctx context.Context) error {
+			v, err := f(ctx)
+			results <- result{v: v, attempt: attempt, err: err}
+			return nil
+		})
+	}
+
+	launch()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var errs []error
+	for finished := 0; finished < launched; {
+		select {
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				g.cancel()
+				return r.v, r.attempt, nil
+			}
+			errs = append(errs, r.err)
+			if finished == launched && launched < maxAttempts && ctx.Err() == nil {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				launch()
+				timer.Reset(delay)
+			}
+		case <-timer.C:
+			if launched < maxAttempts && ctx.Err() == nil {
+				launch()
+				timer.Reset(delay)
+			}
+		}
+	}
+	g.Wait()
+	var zero T
+	return zero, -1, fmt.Errorf("errgroup: all %d hedged attempts failed: %w", launched, errors.Join(errs...))
+}
@@ -0,0 +1,90 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	var (
+		calls    int32
+		canceled = make(chan struct{}, 1)
+	)
+	v, attempt, err := Hedge(context.Background(), 10*time.Millisecond, 3, func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The first attempt hangs until it is canceled.
+			<-ctx.Done()
+			canceled <- struct{}{}
+			return "", ctx.Err()
+		}
+		return "fast", nil
+	})
+	if err != nil || v != "fast" || attempt != 1 {
+		t.Fatalf("Hedge() = %q, %d, %v; want %q, 1, nil", v, attempt, err, "fast")
+	}
+	select {
+	case <-canceled:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for the losing attempt to be canceled")
+	}
+}
+
+func TestHedgeNoHedgingNeeded(t *testing.T) {
+	var calls int32
+	v, attempt, err := Hedge(context.Background(), time.Hour, 3, func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 42, nil
+	})
+	if err != nil || v != 42 || attempt != 0 {
+		t.Fatalf("Hedge() = %d, %d, %v; want 42, 0, nil", v, attempt, err)
+	}
+	if calls != 1 {
+		t.Fatalf("f called %d times; want 1", calls)
+	}
+}
+
+func TestHedgeFailFast(t *testing.T) {
+	var calls int32
+	errs := []error{errors.New("a"), errors.New("b"), errors.New("c")}
+	start := time.Now()
+	_, attempt, err := Hedge(context.Background(), time.Hour, 3, func(ctx context.Context) (int, error) {
+		return 0, errs[atomic.AddInt32(&calls, 1)-1]
+	})
+	if time.Since(start) > defaultTestTimeout {
+		t.Fatal("failed attempts did not start the next attempt right away")
+	}
+	if attempt != -1 {
+		t.Errorf("Hedge() attempt = %d; want -1", attempt)
+	}
+	for _, want := range errs {
+		if !errors.Is(err, want) {
+			t.Errorf("Hedge() = %v; want it to wrap %v", err, want)
+		}
+	}
+}
+
+func TestHedgePanic(t *testing.T) {
+	var calls int32
+	v, attempt, err := Hedge(context.Background(), time.Hour, 2, func(ctx context.Context) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("2233")
+		}
+		return 1, nil
+	})
+	// The panic fails the first attempt only, which starts the second one
+	// right away.
+	if v != 1 || attempt != 1 || err != nil {
+		t.Fatalf("Hedge() = %d, %d, %v; want 1, 1, <nil>", v, attempt, err)
+	}
+
+	_, _, err = Hedge(context.Background(), time.Hour, 1, func(ctx context.Context) (int, error) {
+		panic("2233")
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Hedge() = %v; want it to wrap a *PanicError", err)
+	}
+}
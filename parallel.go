@@ -0,0 +1,121 @@
+package gosync
+
+import (
+	"context"
+	"runtime"
+)
+
+// chunksPerWorker is how many chunks every worker gets on average. More than
+// one chunk per worker evens out chunks that take longer than others.
+const chunksPerWorker = 4
+
+// parallelChunks splits [0, n) into chunks and calls f for each of them on at
+// most workers goroutines. A worker count below 1 means runtime.GOMAXPROCS(0).
+// The first error cancels the context passed to f and is returned.
+func parallelChunks(ctx context.Context, n, workers int, f func(ctx context.Context, chunk, lo, hi int) error) error {
+	workers, size := chunking(n, workers)
+	g := WithCancel(ctx)
+	g.GOMAXPROCS(workers)
+	for chunk, lo := 0, 0; lo < n; chunk, lo = chunk+1, lo+size {
+		chunk, lo, hi := chunk, lo, lo+size
+		if hi > n {
+			hi = n
+		}
+		g.Go(func(ctx context.Context) error {
+			return f(ctx, chunk, lo, hi)
+		})
+	}
+	return g.Wait()
+}
+
+// chunking returns the number of workers and the chunk size parallelChunks
+// uses for n elements.
+func chunking(n, workers int) (int, int) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	size := (n + workers*chunksPerWorker - 1) / (workers * chunksPerWorker)
+	if size < 1 {
+		size = 1
+	}
+	return workers, size
+}
+
+// ParallelMap calls f for every element of in on at most workers goroutines
+// and returns the results in the order of in. A worker count below 1 means
+// runtime.GOMAXPROCS(0).
+//
+// The first error stops the remaining work and is returned, along with a nil
+// slice.
+func ParallelMap[T, R any](ctx context.Context, in []T, workers int, f func(ctx context.Context, v T) (R, error)) ([]R, error) {
+	out := make([]R, len(in))
+	err := parallelChunks(ctx, len(in), workers, func(ctx context.Context, _, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			r, err := f(ctx, in[i])
+			if err != nil {
+				return err
+			}
+			out[i] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParallelForEach calls f for every element of in on at most workers
+// goroutines. A worker count below 1 means runtime.GOMAXPROCS(0).
+//
+// The first error stops the remaining work and is returned.
+func ParallelForEach[T any](ctx context.Context, in []T, workers int, f func(ctx context.Context, v T) error) error {
+	return parallelChunks(ctx, len(in), workers, func(ctx context.Context, _, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(ctx, in[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ParallelReduce folds in into a single value on at most workers goroutines.
+// Every chunk of in is folded with f, starting from zero, and the results of
+// the chunks are then combined with merge, in the order of in. zero must
+// therefore be an identity for merge, and merge must be associative. A
+// worker count below 1 means runtime.GOMAXPROCS(0).
+//
+// The first error stops the remaining work and is returned.
+func ParallelReduce[T, R any](ctx context.Context, in []T, workers int, zero R, f func(ctx context.Context, acc R, v T) (R, error), merge func(a, b R) R) (R, error) {
+	_, size := chunking(len(in), workers)
+	partial := make([]R, (len(in)+size-1)/size)
+	err := parallelChunks(ctx, len(in), workers, func(ctx context.Context, chunk, lo, hi int) error {
+		acc := zero
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var err error
+			if acc, err = f(ctx, acc, in[i]); err != nil {
+				return err
+			}
+		}
+		partial[chunk] = acc
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	acc := zero
+	for _, p := range partial {
+		acc = merge(acc, p)
+	}
+	return acc, nil
+}
@@ -0,0 +1,108 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelMap(t *testing.T) {
+	in := make([]int, 1000)
+	for i := range in {
+		in[i] = i
+	}
+	for _, workers := range []int{0, 1, 3, 2000} {
+		out, err := ParallelMap(context.Background(), in, workers, func(_ context.Context, v int) (string, error) {
+			return strconv.Itoa(v), nil
+		})
+		if err != nil {
+			t.Fatalf("workers=%d: ParallelMap() = %v; want nil", workers, err)
+		}
+		for i, s := range out {
+			if s != strconv.Itoa(i) {
+				t.Fatalf("workers=%d: out[%d] = %q; want %q", workers, i, s, strconv.Itoa(i))
+			}
+		}
+	}
+
+	out, err := ParallelMap(context.Background(), []int(nil), 4, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	})
+	if err != nil || len(out) != 0 {
+		t.Fatalf("ParallelMap(nil) = %v, %v; want empty, nil", out, err)
+	}
+}
+
+func TestParallelMapError(t *testing.T) {
+	in := make([]int, 10_000)
+	for i := range in {
+		in[i] = i
+	}
+	boom := errors.New("boom")
+	var calls int64
+	out, err := ParallelMap(context.Background(), in, 4, func(_ context.Context, v int) (int, error) {
+		atomic.AddInt64(&calls, 1)
+		if v == 0 {
+			return 0, boom
+		}
+		return v, nil
+	})
+	if err != boom || out != nil {
+		t.Fatalf("ParallelMap() = %v, %v; want nil, %v", out, err, boom)
+	}
+	if n := atomic.LoadInt64(&calls); n == int64(len(in)) {
+		t.Fatal("ParallelMap() did not stop early on error")
+	}
+}
+
+func TestParallelForEach(t *testing.T) {
+	in := make([]int64, 500)
+	for i := range in {
+		in[i] = int64(i)
+	}
+	var sum int64
+	err := ParallelForEach(context.Background(), in, 8, func(_ context.Context, v int64) error {
+		atomic.AddInt64(&sum, v)
+		return nil
+	})
+	if err != nil || sum != 500*499/2 {
+		t.Fatalf("ParallelForEach() = %v, sum %d; want nil, sum %d", err, sum, 500*499/2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ParallelForEach(ctx, in, 8, func(context.Context, int64) error {
+		t.Error("f called with a canceled context")
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("ParallelForEach() = %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	in := make([]string, 100)
+	want := ""
+	for i := range in {
+		in[i] = strconv.Itoa(i % 10)
+		want += in[i]
+	}
+	// Concatenation is associative but not commutative, so this checks
+	// that chunks are merged in order.
+	got, err := ParallelReduce(context.Background(), in, 4, "",
+		func(_ context.Context, acc string, v string) (string, error) { return acc + v, nil },
+		func(a, b string) string { return a + b })
+	if err != nil || got != want {
+		t.Fatalf("ParallelReduce() = %q, %v; want %q, nil", got, err, want)
+	}
+
+	boom := errors.New("boom")
+	_, err = ParallelReduce(context.Background(), in, 4, "",
+		func(_ context.Context, acc string, v string) (string, error) { return "", boom },
+		func(a, b string) string { return a + b })
+	if err != boom {
+		t.Fatalf("ParallelReduce() = %v; want %v", err, boom)
+	}
+}
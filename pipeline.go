@@ -0,0 +1,198 @@
+package gosync
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline runs typed stages connected by bounded channels, such as
+// read → transform → write. All stages run in one Group: the first error, or
+// the cancellation of the context passed to NewPipeline, tears down every
+// stage, and Wait returns once all of them have returned.
+//
+// A Pipeline is built with Source, AddStage and Sink, then awaited with Wait.
+type Pipeline struct {
+	g *Group
+}
+
+// Stage describes a step of a Pipeline transforming values of type In into
+// values of type Out.
+type Stage[In, Out any] struct {
+	// Workers is the number of goroutines running Fn. Values below 1 mean
+	// a single worker.
+	Workers int
+	// Buffer is the capacity of the output channel of the stage.
+	Buffer int
+	// Ordered makes the stage emit its output in the order of its input,
+	// even when several workers run Fn.
+	Ordered bool
+	// Fn transforms a single value. A non-nil error stops the pipeline.
+	Fn func(ctx context.Context, in In) (Out, error)
+}
+
+// NewPipeline returns an empty Pipeline whose stages run with a context
+// derived from ctx.
+func NewPipeline(ctx context.Context) *Pipeline {
+	return &Pipeline{g: WithCancel(ctx)}
+}
+
+// Wait blocks until every stage has returned, then returns the first error
+// (if any) from them. If the pipeline was torn down because its context was
+// canceled, the error is that of the context.
+func (p *Pipeline) Wait() error {
+	return p.g.Wait()
+}
+
+// send delivers v on ch unless ctx is done first.
+func send[T any](ctx context.Context, ch chan<- T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recv receives from ch unless ctx is done first. ok is false once ch is
+// closed or ctx is done.
+func recv[T any](ctx context.Context, ch <-chan T) (v T, ok bool) {
+	select {
+	case v, ok = <-ch:
+		return v, ok
+	case <-ctx.Done():
+		return v, false
+	}
+}
+
+// Source adds the first stage to p. f produces values by calling emit, which
+// blocks while the returned channel, of capacity buffer, is full. The channel
+// is closed once f returns.
+func Source[T any](p *Pipeline, buffer int, f func(ctx context.Context, emit func(v T) error) error) <-chan T {
+	out := make(chan T, buffer)
+	p.g.Go(func(ctx context.Context) error {
+		defer close(out)
+		return f(ctx, func(v T) error {
+			return send(ctx, out, v)
+		})
+	})
+	return out
+}
+
+// AddStage adds s to p, reading from in, which is typically the output of a
+// previous stage. The returned channel is closed once in is drained or the
+// pipeline is torn down.
+func AddStage[In, Out any](p *Pipeline, in <-chan In, s Stage[In, Out]) <-chan Out {
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan Out, s.Buffer)
+	if s.Ordered && workers > 1 {
+		addOrderedStage(p, in, out, workers, s.Fn)
+		return out
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		p.g.Go(func(ctx context.Context) error {
+			defer wg.Done()
+			for {
+				v, ok := recv(ctx, in)
+				if !ok {
+					return ctx.Err()
+				}
+				r, err := s.Fn(ctx, v)
+				if err != nil {
+					return err
+				}
+				if err := send(ctx, out, r); err != nil {
+					return err
+				}
+			}
+		})
+	}
+	p.g.Go(func(context.Context) error {
+		wg.Wait()
+		close(out)
+		return nil
+	})
+	return out
+}
+
+type stageResult[Out any] struct {
+	v   Out
+	err error
+}
+
+type stageJob[In, Out any] struct {
+	v   In
+	res chan stageResult[Out]
+}
+
+// addOrderedStage runs fn on several workers, while a collector emits the
+// results in input order. Every input value gets its own result channel,
+// queued in input order; the queue bounds how far workers run ahead of the
+// collector.
+func addOrderedStage[In, Out any](p *Pipeline, in <-chan In, out chan<- Out, workers int, fn func(ctx context.Context, in In) (Out, error)) {
+	jobs := make(chan stageJob[In, Out])
+	order := make(chan chan stageResult[Out], workers+cap(out))
+
+	p.g.Go(func(ctx context.Context) error {
+		defer close(jobs)
+		defer close(order)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return ctx.Err()
+			}
+			job := stageJob[In, Out]{v: v, res: make(chan stageResult[Out], 1)}
+			if err := send(ctx, order, job.res); err != nil {
+				return err
+			}
+			if err := send(ctx, jobs, job); err != nil {
+				return err
+			}
+		}
+	})
+	for i := 0; i < workers; i++ {
+		p.g.Go(func(ctx context.Context) error {
+			for job := range jobs {
+				r, err := fn(ctx, job.v)
+				job.res <- stageResult[Out]{r, err}
+			}
+			return nil
+		})
+	}
+	p.g.Go(func(ctx context.Context) error {
+		defer close(out)
+		for res := range order {
+			r, ok := recv(ctx, res)
+			if !ok {
+				return ctx.Err()
+			}
+			if r.err != nil {
+				return r.err
+			}
+			if err := send(ctx, out, r.v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Sink adds the last stage to p, calling f for every value read from in.
+func Sink[T any](p *Pipeline, in <-chan T, f func(ctx context.Context, v T) error) {
+	p.g.Go(func(ctx context.Context) error {
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return ctx.Err()
+			}
+			if err := f(ctx, v); err != nil {
+				return err
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// numbers emits 0..n-1.
+func numbers(n int) func(ctx context.Context, emit func(int) error) error {
+	return func(ctx context.Context, emit func(int) error) error {
+		for i := 0; i < n; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// jittery sleeps a little longer for small values, so that workers finish out
+// of order.
+func jittery(ctx context.Context, v int) (string, error) {
+	time.Sleep(time.Duration(10-v%10) * 100 * time.Microsecond)
+	return strconv.Itoa(v), nil
+}
+
+func TestPipelineOrdered(t *testing.T) {
+	p := NewPipeline(context.Background())
+	src := Source(p, 4, numbers(100))
+	strs := AddStage(p, src, Stage[int, string]{Workers: 8, Buffer: 4, Ordered: true, Fn: jittery})
+	var got, want []string
+	Sink(p, strs, func(_ context.Context, s string) error {
+		got = append(got, s)
+		return nil
+	})
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	for i := 0; i < 100; i++ {
+		want = append(want, strconv.Itoa(i))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ordered stage emitted %v; want %v", got, want)
+	}
+}
+
+func TestPipelineUnordered(t *testing.T) {
+	p := NewPipeline(context.Background())
+	src := Source(p, 0, numbers(100))
+	strs := AddStage(p, src, Stage[int, string]{Workers: 8, Fn: jittery})
+	lens := AddStage(p, strs, Stage[string, int]{Fn: func(_ context.Context, s string) (int, error) {
+		return strconv.Atoi(s)
+	}})
+	var got []int
+	Sink(p, lens, func(_ context.Context, v int) error {
+		got = append(got, v)
+		return nil
+	})
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	sort.Ints(got)
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("pipeline emitted %v; want 0..99", got)
+		}
+	}
+	if len(got) != 100 {
+		t.Fatalf("pipeline emitted %d values; want 100", len(got))
+	}
+}
+
+func TestPipelineError(t *testing.T) {
+	for _, ordered := range []bool{false, true} {
+		p := NewPipeline(context.Background())
+		boom := errors.New("boom")
+		// An endless source, only stopped by the teardown.
+		src := Source(p, 1, func(ctx context.Context, emit func(int) error) error {
+			for i := 0; ; i++ {
+				if err := emit(i); err != nil {
+					return err
+				}
+			}
+		})
+		out := AddStage(p, src, Stage[int, int]{Workers: 4, Ordered: ordered, Fn: func(_ context.Context, v int) (int, error) {
+			if v == 50 {
+				return 0, boom
+			}
+			return v, nil
+		}})
+		Sink(p, out, func(context.Context, int) error { return nil })
+
+		done := make(chan error, 1)
+		go func() { done <- p.Wait() }()
+		select {
+		case err := <-done:
+			if err != boom {
+				t.Fatalf("ordered=%v: Wait() = %v; want %v", ordered, err, boom)
+			}
+		case <-time.After(defaultTestTimeout):
+			t.Fatalf("ordered=%v: timeout waiting for the pipeline to tear down", ordered)
+		}
+	}
+}
+
+func TestPipelineCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPipeline(ctx)
+	src := Source(p, 0, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	})
+	out := AddStage(p, src, Stage[int, int]{Workers: 2, Ordered: true, Fn: func(_ context.Context, v int) (int, error) {
+		return v, nil
+	}})
+	Sink(p, out, func(_ context.Context, v int) error {
+		if v == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if err := p.Wait(); err != context.Canceled {
+		t.Fatalf("Wait() = %v; want %v", err, context.Canceled)
+	}
+}
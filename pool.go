@@ -2,6 +2,7 @@ package gosync
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 type PointerWithReset[T any] interface {
@@ -10,9 +11,40 @@ type PointerWithReset[T any] interface {
 	Reset()
 }
 
+// ResetOrDiscarder may be implemented by the objects of a Pool or ResourcePool,
+// in addition to PointerWithReset, to decide for themselves whether they are
+// worth keeping. When the object is returned, ResetOrDiscard is called instead
+// of Reset: it resets the object and reports whether to pool it. Returning
+// false drops the object, e.g. a buffer that grew too large to be kept around.
+type ResetOrDiscarder interface {
+	ResetOrDiscard() bool
+}
+
+// resetObject resets v and reports whether it should be pooled.
+func resetObject[T any, P PointerWithReset[T]](v P) bool {
+	if r, ok := any(v).(ResetOrDiscarder); ok {
+		return r.ResetOrDiscard()
+	}
+	v.Reset()
+	return true
+}
+
 type Pool[T any, P PointerWithReset[T]] struct {
-	pool sync.Pool
-	New  func() P
+	pool  sync.Pool
+	stats poolCounters
+	debug atomic.Pointer[poolDebug[T, P]]
+
+	// floor keeps up to minIdle objects out of reach of the garbage
+	// collector, which may empty pool at any time.
+	minIdle atomic.Int64
+	floorMu sync.Mutex
+	floor   []P
+
+	New func() P
+
+	// Accept, if set, is called by Put before the object is reset. Objects
+	// for which it returns false are dropped instead of pooled.
+	Accept func(P) bool
 }
 
 func NewPool[T any, P PointerWithReset[T]](new func() P) *Pool[T, P] {
@@ -21,18 +53,88 @@ func NewPool[T any, P PointerWithReset[T]](new func() P) *Pool[T, P] {
 	}
 }
 
+// Put resets value and pools it, unless Accept or the ResetOrDiscard method of
+// value asks to drop it.
 func (p *Pool[T, P]) Put(value P) {
-	if value != nil {
-		value.Reset()
-		p.pool.Put(value)
+	if value == nil {
+		p.stats.stripe().nilPuts.Add(1)
+		return
+	}
+	s := p.stats.stripe()
+	s.puts.Add(1)
+	d := p.debugState()
+	var pcs []uintptr
+	if d != nil {
+		pcs = d.checkPut(value)
 	}
+	if (p.Accept != nil && !p.Accept(value)) || !resetObject[T, P](value) {
+		s.discards.Add(1)
+		if d != nil {
+			d.forget(value)
+		}
+		return
+	}
+	if d != nil {
+		d.pool(value, pcs)
+		return
+	}
+	p.release(value)
 }
 
 func (p *Pool[T, P]) Get() P {
-	rv, ok := p.pool.Get().(P)
-	if ok && rv != nil {
+	s := p.stats.stripe()
+	s.gets.Add(1)
+	if d := p.debugState(); d != nil {
+		rv := d.get()
+		if rv != nil {
+			s.hits.Add(1)
+		} else {
+			s.news.Add(1)
+			rv = p.New()
+		}
+		d.track(rv)
+		return rv
+	}
+	if rv := p.acquire(); rv != nil {
+		s.hits.Add(1)
 		return rv
 	}
 
+	s.news.Add(1)
 	return p.New()
 }
+
+// release pools v, which must already be Reset, topping up the floor first.
+func (p *Pool[T, P]) release(v P) {
+	if d := p.debugState(); d != nil {
+		d.pool(v, callers())
+		return
+	}
+	if minIdle := p.minIdle.Load(); minIdle > 0 {
+		p.floorMu.Lock()
+		if int64(len(p.floor)) < minIdle {
+			p.floor = append(p.floor, v)
+			p.floorMu.Unlock()
+			return
+		}
+		p.floorMu.Unlock()
+	}
+	p.pool.Put(v)
+}
+
+// acquire returns a pooled object, or nil if there is none. The floor is only
+// used once the sync.Pool is empty, so that it is still there after a GC.
+func (p *Pool[T, P]) acquire() P {
+	if rv, ok := p.pool.Get().(P); ok && rv != nil {
+		return rv
+	}
+	p.floorMu.Lock()
+	defer p.floorMu.Unlock()
+	if n := len(p.floor); n > 0 {
+		rv := p.floor[n-1]
+		p.floor[n-1] = nil
+		p.floor = p.floor[:n-1]
+		return rv
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package gosync
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// PoolLeak describes an object taken from a Pool in debug mode and not
+// returned yet.
+type PoolLeak struct {
+	// Object is the outstanding object.
+	Object any
+	// Stack is the stack trace of the Get call that returned Object.
+	Stack string
+}
+
+// TestingT is the subset of testing.TB used by Pool.CheckLeaks.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// EnableDebug switches p to debug mode, which is meant for tests and debug
+// builds. In debug mode p
+//
+//   - tracks every object returned by Get, along with the stack of the call,
+//     until it is returned with Put; see Leaks and CheckLeaks,
+//   - panics when an object is passed to Put while it is already pooled,
+//   - poisons every object once it is Reset by Put, until Get hands it out
+//     again, and panics when Get finds that the poison was modified, i.e.
+//     that the object was used after Put.
+//
+// Poisoning fills objects without pointers with a recognizable byte pattern.
+// Objects holding pointers, slices, maps or strings are zeroed instead, as a
+// pattern would corrupt the garbage collector: reads after Put see zero
+// values, and writes through a nil map or an emptied slice panic right away.
+// Get restores the object as it was after Reset. Writes through a copy of a
+// pointer field taken before Put, and writes of the poison itself, still go
+// unnoticed.
+//
+// Pooled objects are kept in a plain list rather than in a sync.Pool, so they
+// survive garbage collections. EnableDebug must be called before p is used.
+// Building with the gosync_pooldebug tag enables debug mode for every Pool.
+func (p *Pool[T, P]) EnableDebug() {
+	p.debug.CompareAndSwap(nil, newPoolDebug[T, P]())
+}
+
+// Leaks returns the objects taken from p and not returned yet. It returns nil
+// unless p is in debug mode.
+func (p *Pool[T, P]) Leaks() []PoolLeak {
+	d := p.debug.Load()
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var leaks []PoolLeak
+	for v, pcs := range d.outstanding {
+		leaks = append(leaks, PoolLeak{Object: v, Stack: formatStack(pcs)})
+	}
+	return leaks
+}
+
+// CheckLeaks reports an error on t for every object taken from p and not
+// returned yet. Tests typically defer it right after enabling debug mode.
+func (p *Pool[T, P]) CheckLeaks(t TestingT) {
+	t.Helper()
+	for _, l := range p.Leaks() {
+		t.Errorf("gosync: pooled object %v was never returned, got at:\n%s", l.Object, l.Stack)
+	}
+}
+
+// debugState returns the debug state of p, or nil if p is not in debug mode.
+func (p *Pool[T, P]) debugState() *poolDebug[T, P] {
+	if d := p.debug.Load(); d != nil || !poolDebugDefault {
+		return d
+	}
+	p.EnableDebug()
+	return p.debug.Load()
+}
+
+// poolPoison is the byte pattern pooled objects without pointers are filled
+// with in debug mode.
+const poolPoison = 0xdb
+
+type pooledObject[T any] struct {
+	// reset is a copy of the object as it was after Reset, restored by Get.
+	// It is typed, so that the garbage collector still sees the pointers of
+	// a poisoned object.
+	reset  T
+	poison []byte
+	put    []uintptr
+}
+
+type poolDebug[T any, P PointerWithReset[T]] struct {
+	mu          sync.Mutex
+	free        []P
+	pooled      map[P]pooledObject[T]
+	outstanding map[P][]uintptr
+	// pattern tells whether objects can be filled with poolPoison, i.e.
+	// whether T holds no pointers.
+	pattern bool
+}
+
+func newPoolDebug[T any, P PointerWithReset[T]]() *poolDebug[T, P] {
+	return &poolDebug[T, P]{
+		pooled:      make(map[P]pooledObject[T]),
+		outstanding: make(map[P][]uintptr),
+		pattern:     !hasPointers(reflect.TypeOf((*T)(nil)).Elem()),
+	}
+}
+
+// hasPointers reports whether values of type t hold pointers the garbage
+// collector needs to see.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
+
+// poison overwrites the object v points to, see EnableDebug.
+func (d *poolDebug[T, P]) poison(v P) {
+	if !d.pattern {
+		var zero T
+		*v = zero
+		return
+	}
+	b := objectBytes[T, P](v)
+	for i := range b {
+		b[i] = poolPoison
+	}
+}
+
+// objectBytes returns the memory of the object v points to.
+func objectBytes[T any, P PointerWithReset[T]](v P) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(v)), unsafe.Sizeof(*v))
+}
+
+// callers returns the stack of the caller of the Pool method calling it.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	return pcs[:runtime.Callers(4, pcs)]
+}
+
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			return b.String()
+		}
+	}
+}
+
+// get takes an object from the free list, or returns nil.
+func (d *poolDebug[T, P]) get() P {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := len(d.free)
+	if n == 0 {
+		return nil
+	}
+	v := d.free[n-1]
+	d.free[n-1] = nil
+	d.free = d.free[:n-1]
+	obj := d.pooled[v]
+	delete(d.pooled, v)
+	if !bytes.Equal(obj.poison, objectBytes[T, P](v)) {
+		panic(fmt.Sprintf("gosync: pooled object %v was modified after Put at:\n%s", v, formatStack(obj.put)))
+	}
+	*v = obj.reset
+	return v
+}
+
+// track records that v was handed out by Get.
+func (d *poolDebug[T, P]) track(v P) {
+	pcs := callers()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.outstanding[v] = pcs
+}
+
+// checkPut panics if v is already pooled, and returns the stack of the caller
+// of Put.
+func (d *poolDebug[T, P]) checkPut(v P) []uintptr {
+	pcs := callers()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if obj, ok := d.pooled[v]; ok {
+		panic(fmt.Sprintf("gosync: object %v passed to Put twice, first at:\n%s", v, formatStack(obj.put)))
+	}
+	return pcs
+}
+
+// forget stops tracking v, an object dropped by Put.
+func (d *poolDebug[T, P]) forget(v P) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.outstanding, v)
+}
+
+// pool poisons and pools v, which must already be reset, put at pcs.
+func (d *poolDebug[T, P]) pool(v P, pcs []uintptr) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.outstanding, v)
+	obj := pooledObject[T]{reset: *v, put: pcs}
+	d.poison(v)
+	obj.poison = append([]byte(nil), objectBytes[T, P](v)...)
+	d.pooled[v] = obj
+	d.free = append(d.free, v)
+}
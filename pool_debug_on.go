@@ -0,0 +1,6 @@
+//go:build gosync_pooldebug
+
+package gosync
+
+// poolDebugDefault enables debug mode for every Pool; see Pool.EnableDebug.
+const poolDebugDefault = true
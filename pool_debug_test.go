@@ -0,0 +1,165 @@
+package gosync
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type debugValue struct {
+	n    int
+	name string
+}
+
+func (v *debugValue) Reset() { *v = debugValue{} }
+
+// fakeT records the errors reported through TestingT.
+type fakeT struct {
+	errs []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func newDebugPool() *Pool[debugValue, *debugValue] {
+	p := NewPool(func() *debugValue { return &debugValue{} })
+	p.EnableDebug()
+	return p
+}
+
+func leakyGet(p *Pool[debugValue, *debugValue]) *debugValue {
+	return p.Get()
+}
+
+func TestPoolDebugLeaks(t *testing.T) {
+	p := newDebugPool()
+	x := p.Get()
+	leaked := leakyGet(p)
+	p.Put(x)
+
+	leaks := p.Leaks()
+	if len(leaks) != 1 || leaks[0].Object != leaked {
+		t.Fatalf("Leaks() = %v; want the object from leakyGet", leaks)
+	}
+	if !strings.Contains(leaks[0].Stack, "leakyGet") {
+		t.Errorf("leak stack does not mention leakyGet:\n%s", leaks[0].Stack)
+	}
+
+	var ft fakeT
+	p.CheckLeaks(&ft)
+	if len(ft.errs) != 1 {
+		t.Fatalf("CheckLeaks() reported %v; want 1 leak", ft.errs)
+	}
+	p.Put(leaked)
+	ft = fakeT{}
+	p.CheckLeaks(&ft)
+	if len(ft.errs) != 0 {
+		t.Fatalf("CheckLeaks() after Put reported %v; want none", ft.errs)
+	}
+}
+
+func TestPoolDebugReuse(t *testing.T) {
+	p := newDebugPool()
+	x := p.Get()
+	x.n = 1
+	p.Put(x)
+	if y := p.Get(); y != x || y.n != 0 {
+		t.Fatalf("Get() = %p %+v; want the reset object %p", y, y, x)
+	}
+	if s := p.Stats(); s.Hits != 1 || s.News != 1 {
+		t.Fatalf("Stats() = %+v; want 1 hit and 1 New", s)
+	}
+}
+
+func expectPanic(t *testing.T, want string, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if s, _ := r.(string); !strings.Contains(s, want) {
+			t.Fatalf("panic = %v; want it to contain %q", r, want)
+		}
+	}()
+	f()
+}
+
+func TestPoolDebugDoublePut(t *testing.T) {
+	p := newDebugPool()
+	x := p.Get()
+	p.Put(x)
+	expectPanic(t, "passed to Put twice", func() { p.Put(x) })
+}
+
+func TestPoolDebugUseAfterPut(t *testing.T) {
+	p := newDebugPool()
+	x := p.Get()
+	p.Put(x)
+	x.name = "still in use"
+	expectPanic(t, "modified after Put", func() { p.Get() })
+}
+
+type plainValue struct {
+	n     int64
+	flags [4]uint8
+}
+
+func (v *plainValue) Reset() { *v = plainValue{n: 1} }
+
+func TestPoolDebugPoison(t *testing.T) {
+	p := NewPool(func() *plainValue { return &plainValue{} })
+	p.EnableDebug()
+	x := p.Get()
+	p.Put(x)
+	// A read after Put sees the poison instead of the reset value.
+	if x.n == 1 || x.flags[0] != poolPoison {
+		t.Fatalf("pooled object = %+v; want it filled with %#x", *x, poolPoison)
+	}
+	if y := p.Get(); y != x || *y != (plainValue{n: 1}) {
+		t.Fatalf("Get() = %p %+v; want the reset object %p", y, y, x)
+	}
+
+	// A write of a single byte is caught.
+	p.Put(x)
+	x.flags[3] = 0
+	expectPanic(t, "modified after Put", func() { p.Get() })
+}
+
+type bufValue struct {
+	buf  []byte
+	tags map[string]int
+}
+
+func (v *bufValue) Reset() {
+	v.buf = v.buf[:0]
+	for k := range v.tags {
+		delete(v.tags, k)
+	}
+}
+
+func TestPoolDebugPoisonPointers(t *testing.T) {
+	p := NewPool(func() *bufValue { return &bufValue{tags: map[string]int{}} })
+	p.EnableDebug()
+	x := p.Get()
+	x.buf = append(x.buf, "hello"...)
+	p.Put(x)
+	// Objects holding pointers are zeroed, so that writes through their
+	// fields fail right away.
+	if x.buf != nil || x.tags != nil {
+		t.Fatalf("pooled object = %+v; want it zeroed", *x)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("write to a map of a pooled object did not panic")
+			}
+		}()
+		x.tags["k"] = 1
+	}()
+	// Get restores the object as it was after Reset, capacity included.
+	if y := p.Get(); y != x || y.tags == nil || len(y.buf) != 0 || cap(y.buf) < 5 {
+		t.Fatalf("Get() = %+v; want the reset object with its buffer", *y)
+	}
+}
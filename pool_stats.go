@@ -0,0 +1,72 @@
+package gosync
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// PoolStats is a snapshot of the counters of a Pool.
+type PoolStats struct {
+	// Gets is the number of calls to Get.
+	Gets uint64
+	// Hits is the number of calls to Get served by a pooled object.
+	Hits uint64
+	// News is the number of calls to New, made by Get on a miss.
+	News uint64
+	// Prefilled is the number of objects created with New by Prefill and
+	// Warmup. They are not counted in News, so that Hits+News == Gets.
+	Prefilled uint64
+	// Puts is the number of objects returned with Put.
+	Puts uint64
+	// NilPuts is the number of nil objects passed to Put and dropped.
+	NilPuts uint64
+	// Discards is the number of objects passed to Put and dropped, by Accept
+	// or ResetOrDiscard. They are counted in Puts as well.
+	Discards uint64
+}
+
+// poolStatStripes is the number of stripes of poolCounters. Every update
+// picks a stripe at random, so concurrent updates rarely hit the same cache
+// line.
+const poolStatStripes = 16
+
+type poolStatStripe struct {
+	gets      atomic.Uint64
+	hits      atomic.Uint64
+	news      atomic.Uint64
+	puts      atomic.Uint64
+	nilPuts   atomic.Uint64
+	discards  atomic.Uint64
+	prefilled atomic.Uint64
+	_         [8]byte // pad to a cache line
+}
+
+type poolCounters struct {
+	stripes [poolStatStripes]poolStatStripe
+}
+
+func (c *poolCounters) stripe() *poolStatStripe {
+	return &c.stripes[rand.Uint32()%poolStatStripes]
+}
+
+func (c *poolCounters) load() PoolStats {
+	var s PoolStats
+	for i := range c.stripes {
+		st := &c.stripes[i]
+		s.Gets += st.gets.Load()
+		s.Hits += st.hits.Load()
+		s.News += st.news.Load()
+		s.Prefilled += st.prefilled.Load()
+		s.Puts += st.puts.Load()
+		s.NilPuts += st.nilPuts.Load()
+		s.Discards += st.discards.Load()
+	}
+	return s
+}
+
+// Stats returns a snapshot of the counters of p. The counters are updated
+// independently, so a snapshot taken while p is in use may be slightly
+// inconsistent, e.g. Hits+News may lag behind Gets.
+func (p *Pool[T, P]) Stats() PoolStats {
+	return p.stats.load()
+}
@@ -89,3 +89,92 @@ func TestPoolNewRace(t *testing.T) {
 		}()
 	}
 }
+
+func TestPoolStats(t *testing.T) {
+	// Disable GC to avoid the victim cache during the test.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	p := NewPool(func() *pooledValue[int] {
+		return &pooledValue[int]{}
+	})
+	x := p.Get()
+	p.Put(x)
+	p.Put(nil)
+	for i := 0; i < 1_000; i++ {
+		p.Put(&pooledValue[int]{})
+	}
+	for i := 0; i < 10; i++ {
+		p.Get()
+	}
+
+	s := p.Stats()
+	if s.Gets != 11 || s.Puts != 1_001 || s.NilPuts != 1 {
+		t.Fatalf("Stats() = %+v; want 11 Gets, 1001 Puts and 1 NilPuts", s)
+	}
+	if s.News < 1 || s.Hits+s.News != s.Gets {
+		t.Fatalf("Stats() = %+v; want at least 1 New and Hits+News == Gets", s)
+	}
+}
+
+// growBuffer drops itself from the pool once it grew beyond maxBufferSize.
+type growBuffer struct {
+	buf []byte
+}
+
+const maxBufferSize = 64
+
+func (b *growBuffer) Reset() { b.buf = b.buf[:0] }
+
+func (b *growBuffer) ResetOrDiscard() bool {
+	b.Reset()
+	return cap(b.buf) <= maxBufferSize
+}
+
+func TestPoolDiscard(t *testing.T) {
+	// The free list of a debug pool makes reuse deterministic.
+	p := NewPool(func() *growBuffer { return &growBuffer{} })
+	p.EnableDebug()
+
+	small := p.Get()
+	small.buf = append(small.buf, "hello"...)
+	p.Put(small)
+	if x := p.Get(); x != small || len(x.buf) != 0 {
+		t.Fatalf("Get() = %p with %d bytes; want the reset small buffer %p", x, len(x.buf), small)
+	}
+
+	large := p.Get()
+	large.buf = make([]byte, 2*maxBufferSize)
+	p.Put(large)
+	if x := p.Get(); x == large {
+		t.Fatal("Get() returned a buffer that ResetOrDiscard asked to drop")
+	}
+
+	// Accept is consulted before ResetOrDiscard resets the buffer.
+	p.Accept = func(b *growBuffer) bool { return len(b.buf) < 8 }
+	long := p.Get()
+	long.buf = append(long.buf, "too long"...)
+	p.Put(long)
+	if x := p.Get(); x == long {
+		t.Fatal("Get() returned a buffer that Accept rejected")
+	}
+
+	if s := p.Stats(); s.Puts != 3 || s.Discards != 2 {
+		t.Fatalf("Stats() = %+v; want 3 Puts and 2 Discards", s)
+	}
+	// Dropped buffers are not leaks; the three buffers got last are.
+	if leaks := p.Leaks(); len(leaks) != 3 {
+		t.Fatalf("Leaks() = %d leaks; want 3", len(leaks))
+	}
+}
+
+func BenchmarkPoolGetPut(b *testing.B) {
+	p := NewPool(func() *pooledValue[int] {
+		return &pooledValue[int]{}
+	})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p.Put(p.Get())
+		}
+	})
+}
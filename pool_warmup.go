@@ -0,0 +1,63 @@
+package gosync
+
+import (
+	"context"
+)
+
+// SetMinIdle makes p keep up to n idle objects in a list of its own, out of
+// reach of the garbage collector. Objects returned with Put or created by
+// Prefill fill this floor first; Get only draws from it once the underlying
+// sync.Pool, which may be emptied by any GC, is empty.
+//
+// Lowering n moves the objects above the new floor to the sync.Pool.
+func (p *Pool[T, P]) SetMinIdle(n int) {
+	if n < 0 {
+		n = 0
+	}
+	p.floorMu.Lock()
+	p.minIdle.Store(int64(n))
+	var surplus []P
+	if len(p.floor) > n {
+		surplus = append(surplus, p.floor[n:]...)
+		for i := n; i < len(p.floor); i++ {
+			p.floor[i] = nil
+		}
+		p.floor = p.floor[:n]
+	}
+	p.floorMu.Unlock()
+	for _, v := range surplus {
+		p.pool.Put(v)
+	}
+}
+
+// Prefill creates n objects with New and pools them, so that the first calls
+// to Get do not have to.
+func (p *Pool[T, P]) Prefill(n int) {
+	for i := 0; i < n; i++ {
+		p.stats.stripe().prefilled.Add(1)
+		p.release(p.New())
+	}
+}
+
+// Warmup is like Prefill, but creates the objects on up to workers goroutines
+// of a Group, for objects that are expensive to create. It stops early and
+// returns ctx.Err() once ctx is done; a panic in New is returned as a
+// *PanicError.
+func (p *Pool[T, P]) Warmup(ctx context.Context, n, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	g := WithCancel(ctx)
+	g.GOMAXPROCS(workers)
+	for i := 0; i < n; i++ {
+		g.Go(func(ctx context.Context) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			p.stats.stripe().prefilled.Add(1)
+			p.release(p.New())
+			return nil
+		})
+	}
+	return g.Wait()
+}
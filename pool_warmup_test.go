@@ -0,0 +1,105 @@
+package gosync
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPoolPrefillSurvivesGC(t *testing.T) {
+	var created int32
+	p := NewPool(func() *pooledValue[int] {
+		atomic.AddInt32(&created, 1)
+		return &pooledValue[int]{}
+	})
+	p.SetMinIdle(4)
+	p.Prefill(4)
+	if created != 4 {
+		t.Fatalf("Prefill(4) created %d objects; want 4", created)
+	}
+
+	// Two GCs empty a sync.Pool, including its victim cache.
+	runtime.GC()
+	runtime.GC()
+	for i := 0; i < 4; i++ {
+		p.Get()
+	}
+	if created != 4 {
+		t.Fatalf("Get() created %d objects after GC; want the 4 prefilled ones to survive", created)
+	}
+	p.Get()
+	if created != 5 {
+		t.Fatalf("created %d objects; want a 5th once the floor is empty", created)
+	}
+	if s := p.Stats(); s.Prefilled != 4 || s.News != 1 || s.Hits != 4 || s.Hits+s.News != s.Gets {
+		t.Fatalf("Stats() = %+v; want 4 Prefilled, 1 New and 4 Hits", s)
+	}
+}
+
+func TestPoolLowerMinIdle(t *testing.T) {
+	if poolDebugDefault {
+		t.Skip("debug mode pools objects in a list of its own, without a floor")
+	}
+	var created int32
+	p := NewPool(func() *pooledValue[int] {
+		atomic.AddInt32(&created, 1)
+		return &pooledValue[int]{}
+	})
+	p.SetMinIdle(4)
+	p.Prefill(4)
+	floorLen := func() int {
+		p.floorMu.Lock()
+		defer p.floorMu.Unlock()
+		return len(p.floor)
+	}
+
+	// The surplus moves to the sync.Pool, the rest stays out of reach of
+	// the GC.
+	p.SetMinIdle(1)
+	if n := floorLen(); n != 1 {
+		t.Fatalf("floor holds %d objects after SetMinIdle(1); want 1", n)
+	}
+	runtime.GC()
+	runtime.GC()
+	p.Get()
+	if created != 4 {
+		t.Fatalf("Get() created an object after SetMinIdle(1) and GC; want it to use the floor")
+	}
+
+	p.Put(&pooledValue[int]{})
+	p.SetMinIdle(0)
+	if n := floorLen(); n != 0 {
+		t.Fatalf("floor holds %d objects after SetMinIdle(0); want 0", n)
+	}
+}
+
+func TestPoolWarmup(t *testing.T) {
+	var created int32
+	p := NewPool(func() *pooledValue[int] {
+		atomic.AddInt32(&created, 1)
+		return &pooledValue[int]{}
+	})
+	p.SetMinIdle(100)
+	if err := p.Warmup(context.Background(), 100, 8); err != nil {
+		t.Fatalf("Warmup() = %v; want nil", err)
+	}
+	if created != 100 {
+		t.Fatalf("Warmup() created %d objects; want 100", created)
+	}
+	for i := 0; i < 100; i++ {
+		p.Get()
+	}
+	if created != 100 {
+		t.Fatalf("Get() created %d objects; want all of them to come from the warm-up", created-100)
+	}
+	if s := p.Stats(); s.Prefilled != 100 || s.Hits != 100 || s.News != 0 {
+		t.Fatalf("Stats() = %+v; want 100 Prefilled and 100 Hits", s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Warmup(ctx, 100, 8); err != context.Canceled {
+		t.Fatalf("Warmup() with canceled context = %v; want %v", err, context.Canceled)
+	}
+}
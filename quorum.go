@@ -0,0 +1,103 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQuorumNotReached is returned by Quorum.Wait when too few tasks succeeded.
+var ErrQuorumNotReached = errors.New("errgroup: quorum not reached")
+
+// Quorum runs tasks producing a T, such as reads from replicas, and succeeds
+// as soon as n of them have succeeded. The remaining tasks are canceled
+// through the context passed to them.
+//
+// Unlike a Group, a failing task does not cancel the others, as the quorum
+// may still be reached without it. A panicking task counts as failed, with a
+// *PanicError.
+type Quorum[T any] struct {
+	g       *Group
+	n       int
+	reached chan struct{} // closed once n tasks succeeded
+
+	mu      sync.Mutex
+	total   int
+	results []T
+	errs    []error
+}
+
+// NewQuorum returns a Quorum needing n successful tasks. The context passed to
+// the tasks is derived from ctx.
+func NewQuorum[T any](ctx context.Context, n int) *Quorum[T] {
+	if n <= 0 {
+		panic("errgroup: quorum must be greater than 0")
+	}
+	q := &Quorum[T]{g: WithCancel(ctx), n: n, reached: make(chan struct{})}
+	q.g.PanicHandler(func(p *PanicError) error {
+		var zero T
+		q.record(zero, p)
+		return nil
+	})
+	return q
+}
+
+// FirstSuccess returns a Quorum satisfied by the first successful task.
+func FirstSuccess[T any](ctx context.Context) *Quorum[T] {
+	return NewQuorum[T](ctx, 1)
+}
+
+// Go calls the given function in a new goroutine.
+func (q *Quorum[T]) Go(f func(ctx context.Context) (T, error)) {
+	q.mu.Lock()
+	q.total++
+	q.mu.Unlock()
+	q.g.Go(func(ctx context.Context) error {
+		v, err := f(ctx)
+		q.record(v, err)
+		return nil
+	})
+}
+
+// record records the outcome of a task.
+func (q *Quorum[T]) record(v T, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	switch {
+	case len(q.results) >= q.n:
+		// A straggler, most likely canceled.
+	case err != nil:
+		q.errs = append(q.errs, err)
+	default:
+		q.results = append(q.results, v)
+		if len(q.results) == q.n {
+			q.g.cancel()
+			close(q.reached)
+		}
+	}
+}
+
+// Wait blocks until the quorum is reached or all tasks have returned. On
+// success it returns the results of the first n successful tasks, in the
+// order they finished, without waiting for the remaining tasks: they are
+// canceled and drain in the background. Otherwise the error wraps
+// ErrQuorumNotReached and the errors of all failed tasks.
+func (q *Quorum[T]) Wait() ([]T, error) {
+	finished := make(chan struct{})
+	go func() {
+		q.g.Wait()
+		close(finished)
+	}()
+	select {
+	case <-q.reached:
+	case <-finished:
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.results) >= q.n {
+		return q.results, nil
+	}
+	return nil, fmt.Errorf("%w: %d of %d tasks succeeded, %d needed: %w",
+		ErrQuorumNotReached, len(q.results), q.total, q.n, errors.Join(q.errs...))
+}
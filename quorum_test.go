@@ -0,0 +1,129 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuorum(t *testing.T) {
+	q := NewQuorum[int](context.Background(), 2)
+	canceled := make(chan int, 2)
+	for i, d := range []time.Duration{0, 5 * time.Millisecond, time.Hour, time.Hour} {
+		i, d := i, d
+		q.Go(func(ctx context.Context) (int, error) {
+			select {
+			case <-time.After(d):
+				return i, nil
+			case <-ctx.Done():
+				canceled <- i
+				return 0, ctx.Err()
+			}
+		})
+	}
+	res, err := q.Wait()
+	if err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(res, want) {
+		t.Fatalf("Wait() = %v; want %v", res, want)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-canceled:
+		case <-time.After(defaultTestTimeout):
+			t.Fatalf("%d stragglers were canceled; want 2", i)
+		}
+	}
+}
+
+func TestQuorumNotReached(t *testing.T) {
+	q := NewQuorum[string](context.Background(), 2)
+	errA, errB := errors.New("a"), errors.New("b")
+	q.Go(func(context.Context) (string, error) { return "", errA })
+	q.Go(func(context.Context) (string, error) { return "ok", nil })
+	q.Go(func(context.Context) (string, error) { return "", errB })
+	res, err := q.Wait()
+	if res != nil {
+		t.Errorf("Wait() = %v; want no results", res)
+	}
+	for _, want := range []error{ErrQuorumNotReached, errA, errB} {
+		if !errors.Is(err, want) {
+			t.Errorf("Wait() = %v; want it to wrap %v", err, want)
+		}
+	}
+}
+
+func TestFirstSuccess(t *testing.T) {
+	q := FirstSuccess[string](context.Background())
+	q.Go(func(context.Context) (string, error) { return "", errors.New("down") })
+	q.Go(func(ctx context.Context) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "replica", nil
+	})
+	q.Go(func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	res, err := q.Wait()
+	if err != nil || len(res) != 1 || res[0] != "replica" {
+		t.Fatalf("Wait() = %v, %v; want [replica], nil", res, err)
+	}
+}
+
+func TestFirstSuccessStraggler(t *testing.T) {
+	q := FirstSuccess[string](context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	q.Go(func(context.Context) (string, error) { return "fast", nil })
+	q.Go(func(context.Context) (string, error) {
+		<-release // ignores cancellation
+		return "slow", nil
+	})
+	returned := make(chan struct{})
+	go func() {
+		if res, err := q.Wait(); err != nil || len(res) != 1 || res[0] != "fast" {
+			t.Errorf("Wait() = %v, %v; want [fast], nil", res, err)
+		}
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("Wait() blocked on a straggler after the quorum was reached")
+	}
+}
+
+func TestQuorumPanic(t *testing.T) {
+	q := NewQuorum[int](context.Background(), 2)
+	q.Go(func(context.Context) (int, error) { panic("replica crashed") })
+	q.Go(func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(5 * time.Millisecond):
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	q.Go(func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(5 * time.Millisecond):
+			return 2, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	if res, err := q.Wait(); err != nil || len(res) != 2 {
+		t.Fatalf("Wait() = %v, %v; want the 2 other results, the panic must not cancel them", res, err)
+	}
+
+	q = FirstSuccess[int](context.Background())
+	q.Go(func(context.Context) (int, error) { panic("replica crashed") })
+	_, err := q.Wait()
+	var p *PanicError
+	if !errors.Is(err, ErrQuorumNotReached) || !errors.As(err, &p) {
+		t.Fatalf("Wait() = %v; want %v wrapping a *PanicError", err, ErrQuorumNotReached)
+	}
+}
@@ -0,0 +1,93 @@
+package gosync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter gates the start of the tasks of a Group. *RateLimiter implements
+// it, and so does *rate.Limiter from golang.org/x/time/rate.
+type Limiter interface {
+	// Wait blocks until the next task may start or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// RateLimiter is a token bucket limiter. The bucket holds up to burst tokens
+// and is refilled at rate tokens per second; every event takes one token.
+//
+// All methods on this type are thread-safe.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate events per second with
+// bursts of up to burst events. The bucket starts full.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 || burst <= 0 {
+		panic("gosync: RateLimiter rate and burst must be positive")
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// advance refills the bucket up to now. l.mu must be held.
+func (l *RateLimiter) advance(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+}
+
+// Allow takes a token if one is available right now and reports whether it
+// did.
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait takes a token, blocking until one is available. If ctx is done first,
+// the token is given back and ctx.Err() is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.advance(time.Now())
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package gosync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := NewRateLimiter(10, 3)
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d = false; want true within burst", i)
+		}
+	}
+	if l.Allow() {
+		t.Fatal("Allow() = true; want false once the burst is used up")
+	}
+	time.Sleep(150 * time.Millisecond)
+	if !l.Allow() {
+		t.Fatal("Allow() = false; want true after the bucket refilled")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(100, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() = %v; want nil", err)
+		}
+	}
+	if d := time.Since(start); d < 45*time.Millisecond {
+		t.Fatalf("6 events at 100/s with burst 1 took %v; want at least 50ms", d)
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	l.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTestShortTimeout)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait() = %v; want %v", err, context.DeadlineExceeded)
+	}
+	// The canceled Wait gave its token back, so the bucket is only short of
+	// the token taken by Allow.
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 || tokens > 0.1 {
+		t.Fatalf("bucket holds %v tokens; want about 0", tokens)
+	}
+}
+
+func TestGroupLimiter(t *testing.T) {
+	var g Group
+	g.GOMAXPROCS(4)
+	g.SetLimiter(NewRateLimiter(100, 2))
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		g.Go(func(context.Context) error { return nil })
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	// 2 tasks start from the burst, the other 6 need 10ms each; allow for
+	// some timer slack.
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("8 tasks at 100/s with burst 2 took %v; want at least 50ms", d)
+	}
+}
+
+func TestGroupLimiterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := WithCancel(ctx)
+	g.SetLimiter(NewRateLimiter(0.001, 1))
+	finished := make(chan error, 3)
+	g.SetHooks(GroupHooks{OnFinish: func(_ TaskInfo, _ time.Duration, err error) {
+		finished <- err
+	}})
+	ran := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		g.Go(func(context.Context) error {
+			ran <- struct{}{}
+			return nil
+		})
+	}
+	<-ran
+	cancel()
+	if err := g.Wait(); err != context.Canceled {
+		t.Fatalf("Wait() = %v; want %v", err, context.Canceled)
+	}
+	if n := len(ran); n != 0 {
+		t.Fatalf("%d more tasks ran after cancel; want 0", n)
+	}
+	if s := g.Stats(); s.Succeeded != 1 || s.Failed != 2 {
+		t.Fatalf("Stats() = %+v; want 1 succeeded and 2 failed", s)
+	}
+	// OnFinish sees the tasks that never ran as well.
+	var canceled int
+	for i := 0; i < 3; i++ {
+		if err := <-finished; err == context.Canceled {
+			canceled++
+		}
+	}
+	if canceled != 2 {
+		t.Fatalf("OnFinish saw %d tasks failed with %v; want 2", canceled, context.Canceled)
+	}
+}
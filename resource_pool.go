@@ -0,0 +1,336 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned by ResourcePool.Get once the pool is closed.
+var ErrPoolClosed = errors.New("gosync: resource pool closed")
+
+// ResourcePoolConfig configures a ResourcePool.
+type ResourcePoolConfig[T any, P PointerWithReset[T]] struct {
+	// New creates a resource. It is required.
+	New func(ctx context.Context) (P, error)
+	// MinSize is the number of resources created up front by
+	// NewResourcePool. IdleTimeout does not shrink the pool below it, and
+	// the reaper replaces resources past MaxLifetime to keep it.
+	MinSize int
+	// MaxSize bounds the number of resources of the pool, idle or in use.
+	// It must be positive.
+	MaxSize int
+
+	// Validate, if set, is called before an idle resource is handed out by
+	// Get. Resources for which it returns false are destroyed.
+	Validate func(v P) bool
+	// IdleTimeout, if positive, destroys resources that stayed idle for
+	// longer, as long as MinSize resources are left.
+	IdleTimeout time.Duration
+	// MaxLifetime, if positive, destroys resources older than that, once
+	// they are idle.
+	MaxLifetime time.Duration
+	// ReapInterval is how often idle resources are checked against
+	// IdleTimeout and MaxLifetime. It defaults to half the smaller of both.
+	ReapInterval time.Duration
+	// Destroy, if set, is called to destroy a resource. By default a
+	// resource implementing io.Closer is closed.
+	Destroy func(v P)
+
+	// clock is replaced by tests.
+	clock clock
+}
+
+// clock abstracts time for the reaper of a ResourcePool.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// idleResource is an idle resource along with the times it was created and
+// returned to the pool.
+type idleResource[P any] struct {
+	v        P
+	created  time.Time
+	returned time.Time
+}
+
+// ResourcePool is a pool of expensive resources, such as connections or file
+// handles. Unlike Pool it never drops idle resources behind the user's back,
+// and bounds the number of resources: Get blocks while MaxSize resources are
+// in use.
+//
+// Resources are Reset when they are returned with Put, like with Pool. Idle
+// resources are destroyed by Close, and once they are stale according to the
+// IdleTimeout and MaxLifetime of the pool.
+//
+// All methods on this type are thread-safe.
+type ResourcePool[T any, P PointerWithReset[T]] struct {
+	cfg ResourcePoolConfig[T, P]
+
+	// sem holds a token for every resource in use; Get blocks while it is
+	// full.
+	sem    chan struct{}
+	closed chan struct{}
+
+	mu      sync.Mutex
+	idle    []idleResource[P]
+	created map[P]time.Time // creation time of resources in use
+	done    bool
+}
+
+// NewResourcePool returns a ResourcePool configured by cfg, holding
+// cfg.MinSize idle resources created with ctx.
+//
+// If cfg sets IdleTimeout or MaxLifetime, a background reaper destroys stale
+// idle resources until ctx is done or the pool is closed.
+func NewResourcePool[T any, P PointerWithReset[T]](ctx context.Context, cfg ResourcePoolConfig[T, P]) (*ResourcePool[T, P], error) {
+	if cfg.New == nil {
+		return nil, errors.New("gosync: ResourcePoolConfig.New is required")
+	}
+	if cfg.MaxSize <= 0 || cfg.MinSize < 0 || cfg.MinSize > cfg.MaxSize {
+		return nil, errors.New("gosync: ResourcePoolConfig needs 0 <= MinSize <= MaxSize and MaxSize > 0")
+	}
+	if cfg.clock == nil {
+		cfg.clock = realClock{}
+	}
+	p := &ResourcePool[T, P]{
+		cfg:     cfg,
+		sem:     make(chan struct{}, cfg.MaxSize),
+		closed:  make(chan struct{}),
+		created: make(map[P]time.Time),
+	}
+	for i := 0; i < cfg.MinSize; i++ {
+		v, err := cfg.New(ctx)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		now := cfg.clock.Now()
+		p.idle = append(p.idle, idleResource[P]{v: v, created: now, returned: now})
+	}
+	if interval := p.reapInterval(); interval > 0 {
+		go p.reaper(ctx, interval)
+	}
+	return p, nil
+}
+
+// Get returns an idle resource, creating one if there is none. If MaxSize
+// resources are in use, Get blocks until one is returned with Put, ctx is done
+// or the pool is closed.
+func (p *ResourcePool[T, P]) Get(ctx context.Context) (P, error) {
+	select {
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	default:
+	}
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	}
+
+	for {
+		p.mu.Lock()
+		if p.done {
+			p.mu.Unlock()
+			<-p.sem
+			return nil, ErrPoolClosed
+		}
+		n := len(p.idle)
+		if n == 0 {
+			p.mu.Unlock()
+			break
+		}
+		r := p.idle[n-1]
+		p.idle[n-1] = idleResource[P]{}
+		p.idle = p.idle[:n-1]
+		stale := p.stale(r, p.cfg.clock.Now(), p.size()+1)
+		if !stale {
+			p.created[r.v] = r.created
+		}
+		p.mu.Unlock()
+
+		if !stale && (p.cfg.Validate == nil || p.cfg.Validate(r.v)) {
+			return r.v, nil
+		}
+		p.mu.Lock()
+		delete(p.created, r.v)
+		p.mu.Unlock()
+		p.destroy(r.v)
+	}
+
+	v, err := p.cfg.New(ctx)
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+	p.mu.Lock()
+	p.created[v] = p.cfg.clock.Now()
+	p.mu.Unlock()
+	return v, nil
+}
+
+// Put resets v and returns it to the pool. Every resource obtained from Get
+// must be returned exactly once, with Put or Discard. Once the pool is closed,
+// once v outlived MaxLifetime, or if v implements ResetOrDiscarder and asks to
+// be dropped, v is destroyed instead.
+//
+// Like with Pool, Put(nil) is a no-op, so that Put may be deferred right
+// after a Get that may fail.
+func (p *ResourcePool[T, P]) Put(v P) {
+	if v == nil {
+		return
+	}
+	defer func() { <-p.sem }()
+	keep := resetObject[T, P](v)
+	now := p.cfg.clock.Now()
+	p.mu.Lock()
+	r := idleResource[P]{v: v, created: p.created[v], returned: now}
+	delete(p.created, v)
+	if !keep || p.done || p.stale(r, now, p.size()+1) {
+		p.mu.Unlock()
+		p.destroy(v)
+		return
+	}
+	p.idle = append(p.idle, r)
+	p.mu.Unlock()
+}
+
+// Discard destroys v, a broken resource obtained from Get, instead of
+// returning it to the pool, making room for a new resource. Discard(nil) is a
+// no-op.
+func (p *ResourcePool[T, P]) Discard(v P) {
+	if v == nil {
+		return
+	}
+	defer func() { <-p.sem }()
+	p.mu.Lock()
+	delete(p.created, v)
+	p.mu.Unlock()
+	p.destroy(v)
+}
+
+// Close closes the pool and destroys all idle resources. Resources in use are
+// destroyed when they are returned with Put. Blocked and future calls to Get
+// return ErrPoolClosed.
+func (p *ResourcePool[T, P]) Close() {
+	p.mu.Lock()
+	if p.done {
+		p.mu.Unlock()
+		return
+	}
+	p.done = true
+	close(p.closed)
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+	for _, r := range idle {
+		p.destroy(r.v)
+	}
+}
+
+func (p *ResourcePool[T, P]) destroy(v P) {
+	if p.cfg.Destroy != nil {
+		p.cfg.Destroy(v)
+		return
+	}
+	if c, ok := any(v).(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// size returns the number of resources of the pool, idle or in use, not
+// counting resources being created or destroyed. p.mu must be held.
+func (p *ResourcePool[T, P]) size() int {
+	return len(p.idle) + len(p.created)
+}
+
+// stale reports whether r is past its MaxLifetime at now, or past its
+// IdleTimeout while the pool holds more than MinSize resources, r included.
+func (p *ResourcePool[T, P]) stale(r idleResource[P], now time.Time, size int) bool {
+	if p.cfg.MaxLifetime > 0 && now.Sub(r.created) >= p.cfg.MaxLifetime {
+		return true
+	}
+	return p.cfg.IdleTimeout > 0 && size > p.cfg.MinSize && now.Sub(r.returned) >= p.cfg.IdleTimeout
+}
+
+func (p *ResourcePool[T, P]) reapInterval() time.Duration {
+	if p.cfg.ReapInterval > 0 {
+		return p.cfg.ReapInterval
+	}
+	d := p.cfg.IdleTimeout
+	if l := p.cfg.MaxLifetime; l > 0 && (d <= 0 || l < d) {
+		d = l
+	}
+	return d / 2
+}
+
+func (p *ResourcePool[T, P]) reaper(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.closed:
+			return
+		case <-p.cfg.clock.After(interval):
+			p.reap(ctx)
+		}
+	}
+}
+
+// reap destroys all stale idle resources, the longest idle first, and creates
+// new ones with ctx if the pool is left with fewer than MinSize resources.
+func (p *ResourcePool[T, P]) reap(ctx context.Context) {
+	now := p.cfg.clock.Now()
+	var stale []P
+	p.mu.Lock()
+	size := p.size()
+	idle := p.idle[:0]
+	for _, r := range p.idle {
+		if p.stale(r, now, size) {
+			stale = append(stale, r.v)
+			size--
+		} else {
+			idle = append(idle, r)
+		}
+	}
+	for i := len(idle); i < len(p.idle); i++ {
+		p.idle[i] = idleResource[P]{}
+	}
+	p.idle = idle
+	missing := p.cfg.MinSize - size
+	p.mu.Unlock()
+
+	// Refill first, so that the pool is back to MinSize by the time the
+	// stale resources are gone.
+	for i := 0; i < missing; i++ {
+		v, err := p.cfg.New(ctx)
+		if err != nil {
+			// Try again on the next tick.
+			break
+		}
+		now := p.cfg.clock.Now()
+		p.mu.Lock()
+		if p.done || p.size() >= p.cfg.MinSize {
+			// Closed, or refilled by Get meanwhile.
+			p.mu.Unlock()
+			p.destroy(v)
+			break
+		}
+		p.idle = append(p.idle, idleResource[P]{v: v, created: now, returned: now})
+		p.mu.Unlock()
+	}
+	for _, v := range stale {
+		p.destroy(v)
+	}
+}
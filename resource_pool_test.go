@@ -0,0 +1,466 @@
+package gosync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testConn struct {
+	id     int
+	used   bool
+	closed int32
+}
+
+func (c *testConn) Reset() { c.used = false }
+
+func (c *testConn) Close() error {
+	atomic.AddInt32(&c.closed, 1)
+	return nil
+}
+
+// newTestConnPool returns a pool of testConns and a func listing all conns it
+// created.
+func newTestConnPool(t *testing.T, minSize, maxSize int) (*ResourcePool[testConn, *testConn], func() []*testConn) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		conns []*testConn
+	)
+	p, err := NewResourcePool(context.Background(), ResourcePoolConfig[testConn, *testConn]{
+		New: func(context.Context) (*testConn, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			c := &testConn{id: len(conns)}
+			conns = append(conns, c)
+			return c, nil
+		},
+		MinSize: minSize,
+		MaxSize: maxSize,
+	})
+	if err != nil {
+		t.Fatalf("NewResourcePool() = %v", err)
+	}
+	return p, func() []*testConn {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*testConn(nil), conns...)
+	}
+}
+
+func TestResourcePoolReuse(t *testing.T) {
+	p, created := newTestConnPool(t, 2, 4)
+	if n := len(created()); n != 2 {
+		t.Fatalf("NewResourcePool() created %d resources; want MinSize 2", n)
+	}
+	ctx := context.Background()
+	c, err := p.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	c.used = true
+	p.Put(c)
+	if c.used {
+		t.Fatal("Put() did not Reset the resource")
+	}
+	if c2, _ := p.Get(ctx); c2 != c {
+		t.Fatalf("Get() = conn %d; want the conn just returned, %d", c2.id, c.id)
+	}
+	if n := len(created()); n != 2 {
+		t.Fatalf("pool created %d resources; want 2", n)
+	}
+}
+
+func TestResourcePoolMaxSize(t *testing.T) {
+	p, created := newTestConnPool(t, 0, 2)
+	ctx := context.Background()
+	c1, _ := p.Get(ctx)
+	c2, _ := p.Get(ctx)
+
+	short, cancel := context.WithTimeout(ctx, defaultTestShortTimeout)
+	defer cancel()
+	if _, err := p.Get(short); err != context.DeadlineExceeded {
+		t.Fatalf("Get() on exhausted pool = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	got := make(chan *testConn)
+	go func() {
+		c, _ := p.Get(ctx)
+		got <- c
+	}()
+	time.Sleep(defaultTestShortTimeout)
+	p.Put(c2)
+	select {
+	case c := <-got:
+		if c != c2 {
+			t.Fatalf("blocked Get() = conn %d; want conn %d", c.id, c2.id)
+		}
+		p.Put(c)
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for the blocked Get() to return")
+	}
+	p.Put(c1)
+	if n := len(created()); n != 2 {
+		t.Fatalf("pool created %d resources; want MaxSize 2", n)
+	}
+
+	// Discard makes room for a new resource.
+	p.Get(ctx)
+	broken, _ := p.Get(ctx)
+	p.Discard(broken)
+	if broken.closed != 1 {
+		t.Fatal("Discard() did not destroy the resource")
+	}
+	short, cancel = context.WithTimeout(ctx, defaultTestShortTimeout)
+	defer cancel()
+	if _, err := p.Get(short); err != nil {
+		t.Fatalf("Get() after Discard() = %v; want a resource", err)
+	}
+}
+
+func TestResourcePoolClose(t *testing.T) {
+	p, created := newTestConnPool(t, 0, 1)
+	ctx := context.Background()
+	inUse, _ := p.Get(ctx)
+
+	blocked := make(chan error)
+	go func() {
+		_, err := p.Get(ctx)
+		blocked <- err
+	}()
+
+	idle, _ := NewResourcePool(ctx, ResourcePoolConfig[testConn, *testConn]{
+		New:     func(context.Context) (*testConn, error) { return &testConn{}, nil },
+		MinSize: 2,
+		MaxSize: 2,
+	})
+	var idleConns []*testConn
+	for _, r := range idle.idle {
+		idleConns = append(idleConns, r.v)
+	}
+	idle.Close()
+	for _, c := range idleConns {
+		if c.closed != 1 {
+			t.Fatal("Close() did not destroy idle resources")
+		}
+	}
+
+	p.Close()
+	select {
+	case err := <-blocked:
+		if err != ErrPoolClosed {
+			t.Fatalf("blocked Get() = %v; want %v", err, ErrPoolClosed)
+		}
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("timeout waiting for Close() to unblock Get()")
+	}
+	if _, err := p.Get(ctx); err != ErrPoolClosed {
+		t.Fatalf("Get() after Close() = %v; want %v", err, ErrPoolClosed)
+	}
+	p.Put(inUse)
+	if inUse.closed != 1 {
+		t.Fatal("Put() after Close() did not destroy the resource")
+	}
+	if n := len(created()); n != 1 {
+		t.Fatalf("pool created %d resources; want 1", n)
+	}
+}
+
+func TestResourcePoolNewError(t *testing.T) {
+	boom := errors.New("boom")
+	fail := true
+	p, err := NewResourcePool(context.Background(), ResourcePoolConfig[testConn, *testConn]{
+		New: func(context.Context) (*testConn, error) {
+			if fail {
+				return nil, boom
+			}
+			return &testConn{}, nil
+		},
+		MaxSize: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewResourcePool() = %v", err)
+	}
+	if _, err := p.Get(context.Background()); err != boom {
+		t.Fatalf("Get() = %v; want %v", err, boom)
+	}
+	// The failed Get must not hold on to the only place in the pool.
+	fail = false
+	if _, err := p.Get(context.Background()); err != nil {
+		t.Fatalf("Get() = %v; want nil", err)
+	}
+
+	if _, err := NewResourcePool(context.Background(), ResourcePoolConfig[testConn, *testConn]{MaxSize: 1}); err == nil {
+		t.Fatal("NewResourcePool() without New = nil error; want error")
+	}
+}
+
+type discardingConn struct {
+	testConn
+	broken bool
+}
+
+func (c *discardingConn) ResetOrDiscard() bool {
+	c.Reset()
+	return !c.broken
+}
+
+func TestResourcePoolResetOrDiscard(t *testing.T) {
+	destroyed := make(chan *discardingConn, 1)
+	p, err := NewResourcePool(context.Background(), ResourcePoolConfig[discardingConn, *discardingConn]{
+		New:     func(context.Context) (*discardingConn, error) { return &discardingConn{}, nil },
+		MaxSize: 1,
+		Destroy: func(c *discardingConn) { destroyed <- c },
+	})
+	if err != nil {
+		t.Fatalf("NewResourcePool() = %v", err)
+	}
+	c, _ := p.Get(context.Background())
+	c.broken = true
+	p.Put(c)
+	if len(destroyed) != 1 || len(p.idle) != 0 {
+		t.Fatal("Put() pooled a resource that ResetOrDiscard asked to drop")
+	}
+	if c2, err := p.Get(context.Background()); err != nil || c2 == c {
+		t.Fatalf("Get() = %p, %v; want a new resource", c2, err)
+	}
+}
+
+func TestResourcePoolPutNil(t *testing.T) {
+	p, _ := newTestConnPool(t, 0, 1)
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		// On a fresh pool, nil must not wait for a place to free.
+		p.Put(nil)
+		p.Discard(nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("Put(nil) or Discard(nil) blocked")
+	}
+
+	// Nor may nil free the place of another resource.
+	c, _ := p.Get(ctx)
+	p.Put(nil)
+	p.Discard(nil)
+	short, cancel := context.WithTimeout(ctx, defaultTestShortTimeout)
+	defer cancel()
+	if _, err := p.Get(short); err != context.DeadlineExceeded {
+		t.Fatalf("Get() with the only resource in use = %v; want %v", err, context.DeadlineExceeded)
+	}
+	p.Put(c)
+}
+
+func TestResourcePoolRace(t *testing.T) {
+	p, created := newTestConnPool(t, 0, 4)
+	var (
+		wg     sync.WaitGroup
+		active int32
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := p.Get(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if n := atomic.AddInt32(&active, 1); n > 4 {
+				t.Errorf("%d resources in use; want at most 4", n)
+			}
+			c.used = true
+			atomic.AddInt32(&active, -1)
+			p.Put(c)
+		}()
+	}
+	wg.Wait()
+	if n := len(created()); n > 4 {
+		t.Fatalf("pool created %d resources; want at most 4", n)
+	}
+}
+
+// fakeClock is a clock whose time only moves on Advance.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeTimer{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing due timers.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+		} else {
+			w.c <- c.now
+		}
+	}
+	c.waiters = waiters
+}
+
+func TestResourcePoolValidate(t *testing.T) {
+	destroyed := make(chan *testConn, 10)
+	p, err := NewResourcePool(context.Background(), ResourcePoolConfig[testConn, *testConn]{
+		New:      func(context.Context) (*testConn, error) { return &testConn{}, nil },
+		MinSize:  2,
+		MaxSize:  2,
+		Validate: func(c *testConn) bool { return c.id == 0 },
+		Destroy:  func(c *testConn) { destroyed <- c },
+	})
+	if err != nil {
+		t.Fatalf("NewResourcePool() = %v", err)
+	}
+	p.idle[0].v.id = 1 // stale, as seen by Validate
+	c, err := p.Get(context.Background())
+	if err != nil || c.id != 0 {
+		t.Fatalf("Get() = %+v, %v; want a valid resource", c, err)
+	}
+	c.id = 1
+	p.Put(c)
+	// Both idle resources are invalid now, so Get destroys them and
+	// creates a new one.
+	c, err = p.Get(context.Background())
+	if err != nil || c.id != 0 {
+		t.Fatalf("Get() = %+v, %v; want a new resource", c, err)
+	}
+	if n := len(destroyed); n != 2 {
+		t.Fatalf("pool destroyed %d resources; want 2", n)
+	}
+}
+
+func TestResourcePoolReaper(t *testing.T) {
+	clk := newFakeClock()
+	destroyed := make(chan *testConn, 10)
+	var n int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, err := NewResourcePool(ctx, ResourcePoolConfig[testConn, *testConn]{
+		New: func(context.Context) (*testConn, error) {
+			return &testConn{id: int(atomic.AddInt32(&n, 1))}, nil
+		},
+		MinSize:      2,
+		MaxSize:      3,
+		IdleTimeout:  time.Minute,
+		MaxLifetime:  time.Hour,
+		ReapInterval: 10 * time.Second,
+		Destroy:      func(c *testConn) { destroyed <- c },
+		clock:        clk,
+	})
+	if err != nil {
+		t.Fatalf("NewResourcePool() = %v", err)
+	}
+	waitDestroyed := func(want ...int) {
+		t.Helper()
+		for _, id := range want {
+			select {
+			case c := <-destroyed:
+				if c.id != id {
+					t.Fatalf("reaped resource %d; want %d", c.id, id)
+				}
+			case <-time.After(defaultTestTimeout):
+				t.Fatalf("timeout waiting for resource %d to be reaped", id)
+			}
+		}
+	}
+	// The reaper goroutine may not have asked for its next tick yet.
+	advance := func(d time.Duration) {
+		t.Helper()
+		for {
+			clk.mu.Lock()
+			n := len(clk.waiters)
+			clk.mu.Unlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(d)
+	}
+	idleCount := func() int {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.idle)
+	}
+
+	// Grow the pool to 3 resources, 2 of them idle: Get takes resources 2
+	// and 1, and creates 3.
+	busy, _ := p.Get(ctx)
+	c1, _ := p.Get(ctx)
+	c3, _ := p.Get(ctx)
+	p.Put(c1)
+	p.Put(c3)
+
+	// After IdleTimeout the longest idle resource is reaped, but the pool
+	// does not shrink below MinSize.
+	for i := 0; i < 6; i++ {
+		advance(10 * time.Second)
+	}
+	waitDestroyed(1)
+	for i := 0; i < 30; i++ {
+		advance(10 * time.Second)
+	}
+	select {
+	case c := <-destroyed:
+		t.Fatalf("resource %d reaped below MinSize", c.id)
+	case <-time.After(defaultTestShortTimeout):
+	}
+	if n := idleCount(); n != 1 {
+		t.Fatalf("%d idle resources after IdleTimeout; want 1", n)
+	}
+
+	// Resources past MaxLifetime are reaped anyway, and replaced to keep
+	// MinSize. The busy resource is destroyed when it is returned.
+	advance(time.Hour)
+	waitDestroyed(3)
+	p.Put(busy)
+	waitDestroyed(busy.id)
+	c, _ := p.Get(ctx)
+	if c.id != 4 {
+		t.Fatalf("Get() = resource %d; want the refilled resource 4", c.id)
+	}
+
+	// Once ctx is done the reaper stops.
+	p.Put(c)
+	cancel()
+	time.Sleep(defaultTestShortTimeout)
+	clk.Advance(2 * time.Hour)
+	select {
+	case c := <-destroyed:
+		t.Fatalf("resource %d reaped after ctx was canceled", c.id)
+	case <-time.After(defaultTestShortTimeout):
+	}
+}
@@ -0,0 +1,67 @@
+package gosync
+
+// minRingSize is the size a ring starts at and never shrinks below.
+const minRingSize = 8
+
+// ring is a FIFO queue stored in a circular slice, used as the backlog of
+// Unbounded and BoundedBuffer. It grows as needed and shrinks once it is
+// mostly empty. Slots of removed values are zeroed, so that the values do not
+// stay reachable through the backing array.
+//
+// The zero ring is empty and ready to use.
+type ring[T any] struct {
+	buf  []T
+	head int
+	n    int
+}
+
+func (r *ring[T]) len() int {
+	return r.n
+}
+
+// push adds v at the back of r.
+func (r *ring[T]) push(v T) {
+	if r.n == len(r.buf) {
+		size := 2 * len(r.buf)
+		if size < minRingSize {
+			size = minRingSize
+		}
+		r.resize(size)
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = v
+	r.n++
+}
+
+// peek returns the value at the front of r, which must not be empty.
+func (r *ring[T]) peek() T {
+	return r.buf[r.head]
+}
+
+// pop removes and returns the value at the front of r, which must not be
+// empty.
+func (r *ring[T]) pop() T {
+	var zero T
+	v := r.buf[r.head]
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	if len(r.buf) > minRingSize && r.n <= len(r.buf)/4 {
+		r.resize(len(r.buf) / 2)
+	}
+	return v
+}
+
+// resize moves the values of r to a new slice of the given size, at least r.n.
+func (r *ring[T]) resize(size int) {
+	buf := make([]T, size)
+	if r.n > 0 {
+		if end := r.head + r.n; end <= len(r.buf) {
+			copy(buf, r.buf[r.head:end])
+		} else {
+			k := copy(buf, r.buf[r.head:])
+			copy(buf[k:], r.buf[:r.n-k])
+		}
+	}
+	r.buf = buf
+	r.head = 0
+}
@@ -0,0 +1,51 @@
+package gosync
+
+import "testing"
+
+func TestRing(t *testing.T) {
+	var r ring[int]
+	next, want := 0, 0
+	// Interleave pushes and pops so the ring wraps around while it grows and
+	// shrinks.
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 100; i++ {
+			r.push(next)
+			next++
+			if i%3 == 0 {
+				if v := r.pop(); v != want {
+					t.Fatalf("pop() = %d; want %d", v, want)
+				}
+				want++
+			}
+		}
+		for r.len() > 0 {
+			if v := r.peek(); v != want {
+				t.Fatalf("peek() = %d; want %d", v, want)
+			}
+			if v := r.pop(); v != want {
+				t.Fatalf("pop() = %d; want %d", v, want)
+			}
+			want++
+		}
+		if want != next {
+			t.Fatalf("popped %d values; want %d", want, next)
+		}
+		if len(r.buf) != minRingSize {
+			t.Fatalf("empty ring has %d slots; want it to shrink to %d", len(r.buf), minRingSize)
+		}
+	}
+}
+
+func TestRingZeroesSlots(t *testing.T) {
+	var r ring[*int]
+	for i := 0; i < 5; i++ {
+		r.push(new(int))
+	}
+	r.pop()
+	r.pop()
+	for i, v := range r.buf {
+		if live := i >= r.head && i < r.head+r.n; !live && v != nil {
+			t.Fatalf("slot %d of popped value = %p; want nil", i, v)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gosync
 
 import (
+	"context"
 	"errors"
 	"sync"
 )
@@ -23,7 +24,7 @@ type Unbounded[T any] struct {
 	closed  bool
 	closing bool
 	mu      sync.Mutex
-	backlog []T
+	backlog ring[T]
 }
 
 // NewUnbounded returns a new instance of Unbounded.
@@ -33,6 +34,10 @@ func NewUnbounded[T any]() *Unbounded[T] {
 
 var errBufferClosed = errors.New("Put called on closed buffer.Unbounded")
 
+// ErrBufferDrained is returned by Recv once the buffer is closed and all its
+// values have been received.
+var ErrBufferDrained = errors.New("gosync: buffer closed and drained")
+
 // Put adds t to the unbounded buffer.
 func (b *Unbounded[T]) Put(t T) error {
 	b.mu.Lock()
@@ -40,14 +45,14 @@ func (b *Unbounded[T]) Put(t T) error {
 	if b.closing {
 		return errBufferClosed
 	}
-	if len(b.backlog) == 0 {
+	if b.backlog.len() == 0 {
 		select {
 		case b.c <- t:
 			return nil
 		default:
 		}
 	}
-	b.backlog = append(b.backlog, t)
+	b.backlog.push(t)
 	return nil
 }
 
@@ -57,13 +62,14 @@ func (b *Unbounded[T]) Put(t T) error {
 func (b *Unbounded[T]) Load() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	if len(b.backlog) > 0 {
+	if b.backlog.len() > 0 {
 		select {
-		case b.c <- b.backlog[0]:
-			b.backlog = b.backlog[1:]
+		case b.c <- b.backlog.peek():
+			b.backlog.pop()
 		default:
 		}
 	} else if b.closing && !b.closed {
+		b.closed = true
 		close(b.c)
 	}
 }
@@ -90,8 +96,57 @@ func (b *Unbounded[T]) Close() {
 		return
 	}
 	b.closing = true
-	if len(b.backlog) == 0 {
+	if b.backlog.len() == 0 {
 		b.closed = true
 		close(b.c)
 	}
 }
+
+// Recv blocks until a value is available and returns it, calling Load on
+// behalf of the caller. It returns ErrBufferDrained once the buffer is closed
+// and drained, or ctx.Err() if ctx is done first.
+//
+// Recv may be mixed with reads from the channel returned by Get, as long as
+// those reads are followed by Load.
+func (b *Unbounded[T]) Recv(ctx context.Context) (T, error) {
+	select {
+	case t, ok := <-b.c:
+		if !ok {
+			return t, ErrBufferDrained
+		}
+		b.Load()
+		return t, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// TryRecv is like Recv, but does not block: it reports false if no value is
+// available right now or the buffer is closed and drained.
+func (b *Unbounded[T]) TryRecv() (T, bool) {
+	select {
+	case t, ok := <-b.c:
+		if ok {
+			b.Load()
+		}
+		return t, ok
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+// All returns an iterator, in the shape of a range-over-func sequence, that
+// yields the values of the buffer as they are received with Recv. It stops
+// once the buffer is closed and drained, ctx is done, or yield returns false.
+func (b *Unbounded[T]) All(ctx context.Context) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for {
+			t, err := b.Recv(ctx)
+			if err != nil || !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package gosync
 
 import (
+	"context"
 	"reflect"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -119,3 +123,238 @@ func TestClose(t *testing.T) {
 	}
 	ub.Close() // ignored
 }
+
+func TestRecv(t *testing.T) {
+	ub := NewUnbounded[int]()
+	for i := 0; i < 3; i++ {
+		ub.Put(i)
+	}
+	ub.Close()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if v, err := ub.Recv(ctx); v != i || err != nil {
+			t.Fatalf("Unbounded.Recv() = %v, %v; want %v, <nil>", v, err, i)
+		}
+	}
+	if _, err := ub.Recv(ctx); err != ErrBufferDrained {
+		t.Fatalf("Unbounded.Recv() on drained buffer = %v; want %v", err, ErrBufferDrained)
+	}
+
+	ub = NewUnbounded[int]()
+	short, cancel := context.WithTimeout(ctx, defaultTestShortTimeout)
+	defer cancel()
+	if _, err := ub.Recv(short); err != context.DeadlineExceeded {
+		t.Fatalf("Unbounded.Recv() on empty buffer = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTryRecv(t *testing.T) {
+	ub := NewUnbounded[int]()
+	if v, ok := ub.TryRecv(); ok {
+		t.Fatalf("Unbounded.TryRecv() on empty buffer = %v, true; want false", v)
+	}
+	ub.Put(1)
+	ub.Put(2)
+	for i := 1; i <= 2; i++ {
+		if v, ok := ub.TryRecv(); v != i || !ok {
+			t.Fatalf("Unbounded.TryRecv() = %v, %v; want %v, true", v, ok, i)
+		}
+	}
+	ub.Close()
+	if v, ok := ub.TryRecv(); ok {
+		t.Fatalf("Unbounded.TryRecv() on closed buffer = %v, true; want false", v)
+	}
+}
+
+func TestAll(t *testing.T) {
+	ub := NewUnbounded[int]()
+	for i := 0; i < 5; i++ {
+		ub.Put(i)
+	}
+	var reads []int
+	ub.All(context.Background())(func(v int) bool {
+		reads = append(reads, v)
+		return v < 2
+	})
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(reads, want) {
+		t.Fatalf("All() yielded %v; want %v", reads, want)
+	}
+
+	// The values not taken by the stopped iterator are still there.
+	ub.Close()
+	reads = nil
+	ub.All(context.Background())(func(v int) bool {
+		reads = append(reads, v)
+		return true
+	})
+	if want := []int{3, 4}; !reflect.DeepEqual(reads, want) {
+		t.Fatalf("All() yielded %v; want %v", reads, want)
+	}
+}
+
+// TestRecvMixed reads the buffer with Recv, TryRecv and raw Get reads at the
+// same time and makes sure that every value is read exactly once.
+func TestRecvMixed(t *testing.T) {
+	ub := NewUnbounded[int]()
+	var (
+		mu    sync.Mutex
+		reads []int
+		wg    sync.WaitGroup
+	)
+	read := func(v int) {
+		mu.Lock()
+		reads = append(reads, v)
+		mu.Unlock()
+	}
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		for {
+			v, err := ub.Recv(context.Background())
+			if err != nil {
+				return
+			}
+			read(v)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for v := range ub.Get() {
+			read(v)
+			ub.Load()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		ub.All(context.Background())(func(v int) bool {
+			read(v)
+			return true
+		})
+	}()
+	stop := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		for {
+			if v, ok := ub.TryRecv(); ok {
+				read(v)
+				continue
+			}
+			select {
+			case <-stop:
+				return
+			default:
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	var writers sync.WaitGroup
+	writers.Add(numWriters)
+	for i := 0; i < numWriters; i++ {
+		go func(index int) {
+			defer writers.Done()
+			for j := 0; j < numWrites; j++ {
+				ub.Put(index)
+			}
+		}(i)
+	}
+	writers.Wait()
+	ub.Close()
+	close(stop)
+	wg.Wait()
+
+	sort.Ints(reads)
+	if !reflect.DeepEqual(reads, wantReads) {
+		t.Errorf("reads: %#v, wantReads: %#v", reads, wantReads)
+	}
+}
+
+// payload is large enough not to be allocated by the tiny allocator, which
+// would keep it alive along with its neighbors.
+type payload struct {
+	b [32]byte
+}
+
+// putPayloads puts n payloads into ub, counting the ones that get collected.
+func putPayloads(ub *Unbounded[*payload], n int, collected *int32) {
+	for i := 0; i < n; i++ {
+		p := new(payload)
+		runtime.SetFinalizer(p, func(*payload) { atomic.AddInt32(collected, 1) })
+		ub.Put(p)
+	}
+}
+
+// TestUnboundedReleasesValues makes sure that values read from the buffer
+// are not kept reachable by its backlog.
+func TestUnboundedReleasesValues(t *testing.T) {
+	const n = 100
+	ub := NewUnbounded[*payload]()
+	var collected int32
+	putPayloads(ub, n, &collected)
+	// Keep the last value in the buffer, so the backlog is still in use.
+	for i := 0; i < n-1; i++ {
+		if _, ok := ub.TryRecv(); !ok {
+			t.Fatalf("Unbounded.TryRecv() = false after %d values; want %d values", i, n)
+		}
+	}
+
+	deadline := time.Now().Add(defaultTestTimeout)
+	for atomic.LoadInt32(&collected) < n-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d values read from the buffer were collected", atomic.LoadInt32(&collected), n-1)
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	runtime.KeepAlive(ub)
+}
+
+// BenchmarkUnboundedBurst puts bursts of values into the buffer, then drains
+// it, making the backlog grow and shrink.
+func BenchmarkUnboundedBurst(b *testing.B) {
+	ub := NewUnbounded[int]()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1_000; j++ {
+			ub.Put(j)
+		}
+		for j := 0; j < 1_000; j++ {
+			<-ub.Get()
+			ub.Load()
+		}
+	}
+}
+
+// BenchmarkUnboundedSteady keeps a backlog of a constant size, as with a
+// consumer that keeps up with its producer.
+func BenchmarkUnboundedSteady(b *testing.B) {
+	ub := NewUnbounded[int]()
+	for j := 0; j < 64; j++ {
+		ub.Put(j)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ub.Put(i)
+		<-ub.Get()
+		ub.Load()
+	}
+}
+
+func BenchmarkUnboundedParallel(b *testing.B) {
+	ub := NewUnbounded[int]()
+	done := make(chan struct{})
+	go func() {
+		for range ub.Get() {
+			ub.Load()
+		}
+		close(done)
+	}()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ub.Put(0)
+		}
+	})
+	ub.Close()
+	<-done
+}